@@ -0,0 +1,550 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// abiDir holds contract ABIs named by the contract address, e.g. 0x6b175474e89094c44da98b954eedeac495271d0f.json
+var abiDir = "abi"
+
+// lookupABI finds the ABI of the contract at addr in abiDir. File names may be lower case or checksummed.
+// It returns the path of the file, and an error if the file is there but isn't a valid ABI
+func lookupABI(addr common.Address) (*abi.ABI, string, error) {
+	for _, name := range []string{strings.ToLower(addr.Hex()), addr.Hex()} {
+		path := filepath.Join(abiDir, name+".json")
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		parsed, err := abi.JSON(bytes.NewReader(buf))
+		if err != nil {
+			return nil, path, fmt.Errorf("the ABI file %s is invalid: %v", path, err)
+		}
+		return &parsed, path, nil
+	}
+	return nil, "", nil
+}
+
+// calldataMethods finds the methods the selector at the start of data may call on the contract to.
+// The ABI uploaded with the request, which describes the recipient of the transaction, wins over the
+// one stored for the contract, which wins over the selector database. exact tells whether the guess
+// has to explain every byte of the arguments to count
+func (s *Splain) calldataMethods(data, to []byte) (methods []abi.Method, source string, exact bool) {
+	var contract *abi.ABI
+	if bytes.Equal(to, s.to) {
+		contract, source = s.abi, "the ABI uploaded with the request"
+	}
+	if contract == nil && len(to) == common.AddressLength {
+		var err error
+		if contract, source, err = lookupABI(common.BytesToAddress(to)); err != nil && s.abiErr == nil {
+			s.abiErr = err
+		}
+	}
+	if contract != nil {
+		if method, err := contract.MethodById(data[:4]); err == nil {
+			return []abi.Method{*method}, source, false
+		}
+	}
+	source = "the offline selector database"
+	if s.abiErr != nil {
+		source += fmt.Sprintf(". WARNING: %v", s.abiErr)
+	}
+	return selectorMethods(data), source, true
+}
+
+// maxCallDepth is how deep calls passed as bytes arguments of other calls are decoded
+var maxCallDepth = 4
+
+// maxABIParts caps the parts decoded for a transaction, counting every nested call. Offsets in
+// crafted data can point many times at the same bytes, which would otherwise multiply the work
+const maxABIParts = 10000
+
+// decodeCall splits calldata for the contract to into the selector, the plain words summary of
+// standard token calls and the parts of the arguments. depth is 0 for the data of the transaction
+// and 1 more for every call the data is wrapped in. It returns nil if the data doesn't decode
+func (s *Splain) decodeCall(data, to []byte, depth int) []abiPart {
+	if len(data) < 4 {
+		return nil
+	}
+	if s.abiParts >= maxABIParts {
+		return nil
+	}
+	methods, source, exact := s.calldataMethods(data, to)
+
+	// selectors collide, keep every method the arguments decode as and show the first
+	var decoded []abi.Method
+	var parts []abiPart
+	for _, method := range methods {
+		w := abiWalker{data: data[4:], to: to, limit: maxABIParts - s.abiParts}
+		p, err := w.walk(method.Inputs)
+		s.abiParts += len(w.parts)
+		if err != nil || exact && abiFiller(p) {
+			continue
+		}
+		if decoded == nil {
+			parts = p
+		}
+		decoded = append(decoded, method)
+	}
+	if len(decoded) == 0 {
+		return nil
+	}
+	method := &decoded[0]
+	s.nestedCalls(parts, depth)
+
+	sel := abiPart{off: 0, n: 4}
+	sel.txt = fmt.Sprintf("Function: %s", methodSignature(method))
+	if depth > 0 {
+		sel.txt = fmt.Sprintf("Call %s: %s", common.BytesToAddress(to).Hex(), methodSignature(method))
+	}
+	sel.more = fmt.Sprintf("The first 4 bytes of the data are the function selector, the start of the Keccak-256 hash of %s. Decoded with %s", method.Sig, source)
+	if len(decoded) > 1 {
+		others := make([]string, len(decoded)-1)
+		for i := range others {
+			others[i] = decoded[i+1].Sig
+		}
+		sel.txt += " or " + strings.Join(others, " or ")
+		sel.more = fmt.Sprintf("Selector collision: the data decodes as %d known functions with this selector, the arguments are shown for the first. %s", len(decoded), sel.more)
+	}
+	call := []abiPart{sel}
+
+	// standard token calls are summed up in plain words before their arguments
+	if txt, more := tokenCallInfo(method, data[4:], to); txt != "" {
+		call = append(call, abiPart{off: 4, txt: txt, more: more})
+	}
+	return append(call, shiftParts(parts, 4)...)
+}
+
+// nestedCalls decodes the bytes arguments among parts that are calldata themselves. It runs once the
+// arguments decoded without overlaps, so every byte is decoded at most once per level
+func (s *Splain) nestedCalls(parts []abiPart, depth int) {
+	if depth >= maxCallDepth {
+		return
+	}
+	for i := range parts {
+		p := &parts[i]
+		if p.nested == nil {
+			continue
+		}
+		call := s.decodeCall(p.nested.val, p.nested.to, depth+1)
+		if call == nil {
+			continue
+		}
+		p.txt = fmt.Sprintf("%s (bytes): %s", p.nested.name, call[0].txt)
+		p.more = shortNestedCall
+		p.call = shiftParts(call, p.off)
+	}
+}
+
+// shiftParts moves parts, and the calls nested in them, d bytes further
+func shiftParts(parts []abiPart, d int) []abiPart {
+	for i := range parts {
+		parts[i].off += d
+		parts[i].call = shiftParts(parts[i].call, d)
+	}
+	return parts
+}
+
+// addCalldata adds a node for the function selector and for every word of the ABI encoded arguments
+// of the data field. It returns false, without adding anything, if there is no ABI the data decodes with
+func (s *Splain) addCalldata(it rlpItem, noncanon string, verbose bool) bool {
+	data := it.content
+	parts := s.decodeCall(data, s.to, 0)
+	if parts == nil {
+		return false
+	}
+
+	// the rlp prefix gets its own node when verbose, otherwise it goes with the selector
+	start := s.pos
+	i := len(it.enc) - len(data)
+	if verbose {
+		addRLPNode(s, it.enc)
+	}
+	tok := Token{Hex: Hex(it.enc[s.pos-start : i+4]), Text: parts[0].txt, More: parts[0].more}
+	if verbose {
+		tok.More += ". " + verboseCalldata
+	}
+	s.warnNonCanonical(&tok, start, noncanon)
+	s.addToken(tok, i+4-(s.pos-start))
+
+	for _, p := range parts[1:] {
+		tok := Token{Hex: Hex(data[p.off : p.off+p.n]), Text: p.txt, More: p.more}
+		tok.Children = callTokens(p.call, data, s.pos-p.off, 1)
+		s.addToken(tok, p.n)
+	}
+	return true
+}
+
+// callTokens turns the parts of a nested call into the child tokens of the bytes argument holding
+// it. base is the offset of data in the raw input
+func callTokens(parts []abiPart, data []byte, base, depth int) []Token {
+	var tokens []Token
+	for _, p := range parts {
+		tokens = append(tokens, Token{
+			Hex:      Hex(data[p.off : p.off+p.n]),
+			Text:     p.txt,
+			More:     p.more,
+			Offset:   base + p.off,
+			Length:   p.n,
+			Field:    "input",
+			Parent:   -1,
+			Depth:    depth,
+			Children: callTokens(p.call, data, base, depth+1),
+		})
+	}
+	return tokens
+}
+
+// methodSignature is the signature of method with the argument names, e.g. transfer(address to, uint256 amount)
+func methodSignature(method *abi.Method) string {
+	args := make([]string, len(method.Inputs))
+	for i, arg := range method.Inputs {
+		args[i] = strings.TrimSpace(arg.Type.String() + " " + arg.Name)
+	}
+	return fmt.Sprintf("%s(%s)", method.RawName, strings.Join(args, ", "))
+}
+
+// abiPart is a run of n bytes at off in the ABI encoded arguments and what it means
+type abiPart struct {
+	off  int
+	n    int
+	txt  string
+	more string
+
+	filler bool      // bytes that are not part of any argument
+	nested *bytesArg // the value of a bytes argument, which may be calldata itself
+	call   []abiPart // the call in a bytes argument that is calldata itself
+}
+
+// bytesArg is a bytes argument and the contract it would be calldata for
+type bytesArg struct {
+	name string
+	val  []byte
+	to   []byte
+}
+
+// abiFiller tells whether any of parts is not part of an argument
+func abiFiller(parts []abiPart) bool {
+	for _, p := range parts {
+		if p.filler {
+			return true
+		}
+	}
+	return false
+}
+
+// walk splits ABI encoded arguments into the static words, offsets, lengths and tail data of
+// every argument, in the order they are found in w.data. Bytes no argument points to are parts too,
+// so the parts cover all of the data. It fails if the data doesn't decode strictly as args
+func (w *abiWalker) walk(args abi.Arguments) ([]abiPart, error) {
+	data := w.data
+	elems := make([]abi.Type, len(args))
+	names := make([]string, len(args))
+	for i, arg := range args {
+		elems[i] = arg.Type
+		names[i] = arg.Name
+		if names[i] == "" {
+			names[i] = fmt.Sprintf("arg%d", i)
+		}
+	}
+	if err := w.tuple(elems, names, 0); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(w.parts, func(i, j int) bool { return w.parts[i].off < w.parts[j].off })
+	var parts []abiPart
+	pos := 0
+	for _, p := range w.parts {
+		if p.off < pos {
+			return nil, fmt.Errorf("%s overlaps another argument", p.txt)
+		}
+		if p.off > pos {
+			parts = append(parts, abiPart{off: pos, n: p.off - pos, txt: "Unused Data", more: shortUnusedCalldata, filler: true})
+		}
+		parts = append(parts, p)
+		pos = p.off + p.n
+	}
+	if pos < len(data) {
+		parts = append(parts, abiPart{off: pos, n: len(data) - pos, txt: "Extra Data", more: shortExtraCalldata, filler: true})
+	}
+	return parts, nil
+}
+
+// abiWalker follows the head/tail layout of the ABI encoding and collects its parts
+type abiWalker struct {
+	data  []byte
+	parts []abiPart
+	limit int // the most parts to collect
+
+	// bytes arguments that are calldata themselves are decoded as a call to the last address
+	// argument before them in the same tuple, or to the contract called, to
+	to   []byte
+	addr []byte
+}
+
+func (w *abiWalker) add(off, n int, txt, more string) {
+	w.parts = append(w.parts, abiPart{off: off, n: n, txt: txt, more: more})
+}
+
+// word reads the 32 byte word at off
+func (w *abiWalker) word(off int, name string) ([]byte, error) {
+	if len(w.parts) >= w.limit {
+		return nil, fmt.Errorf("%s: more than %d values to decode", name, maxABIParts)
+	}
+	if off < 0 || off+32 > len(w.data) {
+		return nil, fmt.Errorf("%s: the calldata ends before byte %d", name, off+32)
+	}
+	return w.data[off : off+32], nil
+}
+
+// size reads the offset or length word at off
+func (w *abiWalker) size(off int, name string) (int, error) {
+	word, err := w.word(off, name)
+	if err != nil {
+		return 0, err
+	}
+	n := new(big.Int).SetBytes(word)
+	if !n.IsInt64() || n.Int64() > int64(len(w.data)) {
+		return 0, fmt.Errorf("%s: %d is beyond the end of the calldata", name, n)
+	}
+	return int(n.Int64()), nil
+}
+
+// tuple walks the values of a tuple, or of the arguments, whose head starts at start
+func (w *abiWalker) tuple(elems []abi.Type, names []string, start int) error {
+	defer func(addr []byte) { w.addr = addr }(w.addr)
+	w.addr = nil
+	head := start
+	for i, t := range elems {
+		if !abiDynamic(t) {
+			if err := w.static(t, names[i], head); err != nil {
+				return err
+			}
+			head += abiSize(t)
+			continue
+		}
+		// dynamic values are in the tail, the head holds where relative to the start of the tuple
+		off, err := w.size(head, names[i])
+		if err != nil {
+			return err
+		}
+		w.add(head, 32, fmt.Sprintf("%s (%s): offset %d", names[i], t.String(), off), shortABIOffset)
+		if err := w.dynamic(t, names[i], start+off); err != nil {
+			return err
+		}
+		head += 32
+	}
+	return nil
+}
+
+// static walks a value that is encoded in place
+func (w *abiWalker) static(t abi.Type, name string, off int) error {
+	switch t.T {
+	case abi.ArrayTy:
+		n := abiSize(*t.Elem)
+		for i := 0; i < t.Size; i++ {
+			if err := w.static(*t.Elem, fmt.Sprintf("%s[%d]", name, i), off+i*n); err != nil {
+				return err
+			}
+		}
+		return nil
+	case abi.TupleTy:
+		for i, e := range t.TupleElems {
+			if err := w.static(*e, tupleName(t, name, i), off); err != nil {
+				return err
+			}
+			off += abiSize(*e)
+		}
+		return nil
+	}
+
+	word, err := w.word(off, name)
+	if err != nil {
+		return err
+	}
+	val, err := abiWordValue(t, word)
+	if err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	w.add(off, 32, fmt.Sprintf("%s (%s): %s", name, t.String(), val), shortABIWord)
+	if t.T == abi.AddressTy {
+		w.addr = word[12:]
+	}
+	return nil
+}
+
+// dynamic walks a value in the tail, starting at off
+func (w *abiWalker) dynamic(t abi.Type, name string, off int) error {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy:
+		n, err := w.size(off, name+" length")
+		if err != nil {
+			return err
+		}
+		w.add(off, 32, fmt.Sprintf("%s length: %d bytes", name, n), shortABILength)
+		padded := (n + 31) / 32 * 32
+		if off+32+padded > len(w.data) {
+			return fmt.Errorf("%s: the calldata ends before the %d bytes of the value", name, n)
+		}
+		if n == 0 {
+			return nil
+		}
+		val := w.data[off+32 : off+32+n]
+		if !isZero(w.data[off+32+n : off+32+padded]) {
+			return fmt.Errorf("%s: the padding after the value is not zero", name)
+		}
+		txt := fmt.Sprintf("%s (%s): 0x%x", name, t.String(), val)
+		if t.T == abi.StringTy {
+			txt = fmt.Sprintf("%s (%s): %q", name, t.String(), val)
+		}
+		w.add(off+32, padded, txt, shortABIBytes)
+		if t.T == abi.BytesTy {
+			to := w.to
+			if w.addr != nil {
+				to = w.addr
+			}
+			w.parts[len(w.parts)-1].nested = &bytesArg{name, val, to}
+		}
+		return nil
+	case abi.SliceTy:
+		n, err := w.size(off, name+" length")
+		if err != nil {
+			return err
+		}
+		w.add(off, 32, fmt.Sprintf("%s length: %d elements", name, n), shortABILength)
+		// every element takes at least a word, which keeps a huge length from allocating
+		if off+32+n*32 > len(w.data) {
+			return fmt.Errorf("%s: the calldata ends before the %d elements of the array", name, n)
+		}
+		elems := make([]abi.Type, n)
+		names := make([]string, n)
+		for i := range elems {
+			elems[i] = *t.Elem
+			names[i] = fmt.Sprintf("%s[%d]", name, i)
+		}
+		return w.tuple(elems, names, off+32)
+	case abi.ArrayTy:
+		elems := make([]abi.Type, t.Size)
+		names := make([]string, t.Size)
+		for i := range elems {
+			elems[i] = *t.Elem
+			names[i] = fmt.Sprintf("%s[%d]", name, i)
+		}
+		return w.tuple(elems, names, off)
+	case abi.TupleTy:
+		elems := make([]abi.Type, len(t.TupleElems))
+		names := make([]string, len(t.TupleElems))
+		for i, e := range t.TupleElems {
+			elems[i] = *e
+			names[i] = tupleName(t, name, i)
+		}
+		return w.tuple(elems, names, off)
+	}
+	return fmt.Errorf("%s: %s is not a dynamic type", name, t.String())
+}
+
+// tupleName names the i-th component of the tuple name of type t, e.g. order.maker
+func tupleName(t abi.Type, name string, i int) string {
+	if i < len(t.TupleRawNames) && t.TupleRawNames[i] != "" {
+		return name + "." + t.TupleRawNames[i]
+	}
+	return fmt.Sprintf("%s.%d", name, i)
+}
+
+// abiDynamic tells whether values of t are encoded in the tail
+func abiDynamic(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy:
+		return true
+	case abi.ArrayTy:
+		return abiDynamic(*t.Elem)
+	case abi.TupleTy:
+		for _, e := range t.TupleElems {
+			if abiDynamic(*e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// abiSize is the number of bytes a value of t takes in the head
+func abiSize(t abi.Type) int {
+	if abiDynamic(t) {
+		return 32
+	}
+	switch t.T {
+	case abi.ArrayTy:
+		return t.Size * abiSize(*t.Elem)
+	case abi.TupleTy:
+		n := 0
+		for _, e := range t.TupleElems {
+			n += abiSize(*e)
+		}
+		return n
+	}
+	return 32
+}
+
+// abiWordValue formats a static value. Like the decoder solidity generates, it rejects words
+// whose unused bits aren't clean
+func abiWordValue(t abi.Type, word []byte) (string, error) {
+	switch t.T {
+	case abi.UintTy:
+		v := new(big.Int).SetBytes(word)
+		if v.BitLen() > t.Size {
+			return "", fmt.Errorf("%d does not fit in %d bits", v, t.Size)
+		}
+		return v.String(), nil
+	case abi.IntTy:
+		// two's complement
+		v := new(big.Int).SetBytes(word)
+		if word[0]&0x80 != 0 {
+			v.Sub(v, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		if v.Sign() >= 0 && v.BitLen() >= t.Size || v.Sign() < 0 && new(big.Int).Not(v).BitLen() >= t.Size {
+			return "", fmt.Errorf("%d does not fit in %d bits", v, t.Size)
+		}
+		return v.String(), nil
+	case abi.BoolTy:
+		if !isZero(word[:31]) || word[31] > 1 {
+			return "", fmt.Errorf("0x%x is not a bool", word)
+		}
+		return fmt.Sprint(word[31] == 1), nil
+	case abi.AddressTy:
+		if !isZero(word[:12]) {
+			return "", fmt.Errorf("0x%x is not an address", word)
+		}
+		return common.BytesToAddress(word).Hex(), nil
+	case abi.FixedBytesTy:
+		if !isZero(word[t.Size:]) {
+			return "", fmt.Errorf("0x%x is not a bytes%d", word, t.Size)
+		}
+		return "0x" + hex.EncodeToString(word[:t.Size]), nil
+	case abi.FunctionTy:
+		if !isZero(word[24:]) {
+			return "", fmt.Errorf("0x%x is not a function", word)
+		}
+		return fmt.Sprintf("0x%x selector 0x%x", word[:20], word[20:24]), nil
+	}
+	return "0x" + hex.EncodeToString(word), nil
+}
+
+var shortABIWord = "A static argument, encoded in place as a 32 byte word. Numbers are left padded, fixed size bytes are right padded"
+var shortABIOffset = "A dynamic argument is encoded after the static ones. In its place is the offset in bytes of its value, counted from the start of the arguments (or of the enclosing tuple)"
+var shortABILength = "Dynamic values start with their length as a 32 byte word: the number of bytes for bytes and string, the number of elements for arrays"
+var shortABIBytes = "The contents of the bytes or string, right padded with zeros to a multiple of 32 bytes"
+var shortUnusedCalldata = "Bytes between the arguments that no offset points to. The contract's ABI decoder skips them"
+var shortExtraCalldata = "Bytes after the end of the ABI encoded arguments. The contract's ABI decoder ignores them, they are often used to tag transactions"
+var shortNestedCall = "These bytes are calldata themselves, the call this transaction makes the contract make. Its selector and arguments are the children of this node"
+var verboseCalldata = "The arguments follow the selector in the contract ABI encoding. Static values (numbers, addresses, fixed size bytes) are 32 byte words in the head, in the order of the arguments. Dynamic values (bytes, string, arrays) are appended after the head in the tail, and the head holds their offset instead"
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// gas costs from EIP-2929 and EIP-2930
+const (
+	coldAccountAccessCost    = 2600
+	coldSloadCost            = 2100
+	warmStorageReadCost      = 100
+	accessListAddressCost    = 2400
+	accessListStorageKeyCost = 1900
+)
+
+// addAccessList adds nodes for the access list prefix, and the prefix, address and storage keys of every entry
+func (s *Splain) addAccessList(list rlpItem, verbose bool) *ParseError {
+	if list.kind != rlp.List {
+		return &ParseError{s.pos, "Access List: expected a list"}
+	}
+	more := shortAccessList
+	if verbose {
+		more = verboseAccessList
+	}
+	if len(list.content) == 0 {
+		s.addListPrefix(list, "Access List: empty", "The transaction does not pre-warm any addresses or storage slots. Every account and slot is charged the cold access price the first time it is touched")
+		return nil
+	}
+	header := s.addListPrefix(list, "", more)
+
+	addresses, keys := 0, 0
+	for content := list.content; len(content) > 0; addresses++ {
+		tuple, next, err := splitRLP(content)
+		if err != nil {
+			return &ParseError{s.pos, fmt.Sprintf("Access List entry %d: %v", addresses, err)}
+		}
+		if tuple.kind != rlp.List {
+			return &ParseError{s.pos, fmt.Sprintf("Access List entry %d: expected an [address, [storageKeys...]] list", addresses)}
+		}
+		addr, rest, err := splitRLP(tuple.content)
+		if err != nil || addr.kind == rlp.List || len(addr.content) != common.AddressLength {
+			return &ParseError{s.pos + len(tuple.enc) - len(tuple.content), fmt.Sprintf("Access List entry %d: expected a 20 byte address", addresses)}
+		}
+		storage, rest, err := splitRLP(rest)
+		if err != nil || storage.kind != rlp.List || len(rest) != 0 {
+			return &ParseError{s.pos, fmt.Sprintf("Access List entry %d: expected a list of storage keys after the address", addresses)}
+		}
+		keyCount := countRLP(storage.content)
+		address := common.BytesToAddress(addr.content).Hex()
+
+		s.addListPrefix(tuple,
+			fmt.Sprintf("Access List Entry %d: %s with %d storage keys", addresses, address, keyCount),
+			"RLP list prefix of an [address, [storageKeys...]] access list entry")
+		s.addValue(addr, addr.noncanon,
+			fmt.Sprintf("Access List Address: %s", address),
+			fmt.Sprintf("Pre-warms this account. It costs %d gas up front and the first access is then charged %d gas instead of %d (EIP-2929), saving %d gas if the account is touched", accessListAddressCost, warmStorageReadCost, coldAccountAccessCost, coldAccountAccessCost-warmStorageReadCost-accessListAddressCost),
+			verbose)
+		s.addListPrefix(storage,
+			fmt.Sprintf("Storage Keys: %d keys of %s", keyCount, address),
+			"RLP list prefix of the storage slots to pre-warm in this account")
+
+		for rest = storage.content; len(rest) > 0; {
+			key, next, err := splitRLP(rest)
+			if err != nil || key.kind == rlp.List || len(key.content) != common.HashLength {
+				return &ParseError{s.pos, "Access List: expected a 32 byte storage key"}
+			}
+			s.addValue(key, key.noncanon,
+				fmt.Sprintf("Storage Key: 0x%x", key.content),
+				fmt.Sprintf("Pre-warms this storage slot. It costs %d gas up front and the first SLOAD or SSTORE is then charged %d gas instead of %d (EIP-2929), saving %d gas if the slot is touched", accessListStorageKeyCost, warmStorageReadCost, coldSloadCost, coldSloadCost-warmStorageReadCost-accessListStorageKeyCost),
+				verbose)
+			rest = next
+		}
+		keys += keyCount
+		content = next
+	}
+	s.Tokens[header].Text = fmt.Sprintf("Access List: %d addresses and %d storage keys", addresses, keys)
+	return nil
+}
+
+var shortAccessList = "Addresses and storage slots the transaction promises to touch (EIP-2930). They are pre-warmed so their first access is charged the cheaper warm price."
+var verboseAccessList = "Addresses and storage slots the transaction promises to touch (EIP-2930). Since EIP-2929 the first access to an account costs 2600 gas and the first access to a storage slot costs 2100 gas, while any later (warm) access costs 100 gas. An access list pays 2400 gas per address and 1900 gas per storage key up front to make those first accesses warm, a net saving of 100 gas for each entry the transaction really uses. It was introduced to keep contracts that became too expensive after EIP-2929 callable"
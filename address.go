@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo"
+)
+
+// addressClass names an address that behaves differently from an ordinary account or contract
+type addressClass struct {
+	name string
+	more string
+}
+
+// knownAddresses are the addresses recipientInfo explains on their own, filled in by init with the
+// precompiles
+var knownAddresses = map[common.Address]addressClass{
+	{}: {"the zero address", "WARNING: nobody has the key to the zero address, ether sent to it is lost for good. A contract creation leaves the recipient empty instead, a recipient of 20 zero bytes is usually a bug in the sending code"},
+
+	common.HexToAddress("0x000000000000000000000000000000000000dEaD"): {"a burn address", "WARNING: a well known burn address that nobody is believed to have the key to. Ether and tokens are sent to it to take them out of circulation for good"},
+	common.HexToAddress("0xdEAD000000000000000042069420694206942069"): {"a burn address", "WARNING: a well known burn address that nobody is believed to have the key to. Ether and tokens are sent to it to take them out of circulation for good"},
+
+	common.HexToAddress("0x00000000219ab540356cBB839Cbe05303d7705Fa"): {"the beacon chain deposit contract", "The deposit contract that stakes ether for validators on the beacon chain. Deposits can't be withdrawn from it, the validator's withdrawal credentials decide where the stake goes on exit"},
+	common.HexToAddress("0x000F3df6D732807Ef1319fB7B8bB8522d0Beac02"): {"the EIP-4788 beacon roots contract", "A system contract the protocol writes the parent beacon block root to at the start of every block, so contracts can verify proofs about the consensus layer. Calling it reads a root by its timestamp"},
+	common.HexToAddress("0x0000F90827F1C53a10cb7A02335B175320002935"): {"the EIP-2935 block hashes contract", "A system contract the protocol writes the parent block hash to at the start of every block, serving the hashes of the last 8191 blocks. Calling it reads a hash by its block number"},
+	common.HexToAddress("0x00000961Ef480Eb55e80D19ad83579A64c007002"): {"the EIP-7002 withdrawal requests contract", "A system contract that queues withdrawals and exits of validators, requested from their execution layer withdrawal address. It charges a fee that rises with the length of the queue"},
+	common.HexToAddress("0x0000BBdDc7CE488642fb579F8B00f3a590007251"): {"the EIP-7251 consolidation requests contract", "A system contract that queues the merging of one validator's stake into another, requested from their execution layer withdrawal address. It charges a fee that rises with the length of the queue"},
+}
+
+// precompiles are contracts built into every client at the lowest addresses, by address
+var precompiles = map[uint16][2]string{
+	0x01:  {"ecrecover", "recovers the address that signed a message hash"},
+	0x02:  {"sha256", "hashes its input with SHA-256"},
+	0x03:  {"ripemd160", "hashes its input with RIPEMD-160"},
+	0x04:  {"identity", "returns its input, a cheap way to copy memory"},
+	0x05:  {"modexp", "computes modular exponentiation of big numbers (EIP-198)"},
+	0x06:  {"ecAdd", "adds points on the alt_bn128 curve (EIP-196)"},
+	0x07:  {"ecMul", "multiplies a point on the alt_bn128 curve by a scalar (EIP-196)"},
+	0x08:  {"ecPairing", "checks an alt_bn128 pairing, the core of zkSNARK verifiers (EIP-197)"},
+	0x09:  {"blake2f", "runs the BLAKE2b compression function (EIP-152)"},
+	0x0a:  {"point evaluation", "verifies a KZG proof that a blob evaluates to a value at a point (EIP-4844)"},
+	0x0b:  {"bls12381G1Add", "adds points on the BLS12-381 G1 curve (EIP-2537)"},
+	0x0c:  {"bls12381G1MSM", "computes a multi-scalar multiplication on the BLS12-381 G1 curve (EIP-2537)"},
+	0x0d:  {"bls12381G2Add", "adds points on the BLS12-381 G2 curve (EIP-2537)"},
+	0x0e:  {"bls12381G2MSM", "computes a multi-scalar multiplication on the BLS12-381 G2 curve (EIP-2537)"},
+	0x0f:  {"bls12381Pairing", "checks a BLS12-381 pairing, used to verify BLS signatures (EIP-2537)"},
+	0x10:  {"bls12381MapG1", "maps a field element to a point on the BLS12-381 G1 curve (EIP-2537)"},
+	0x11:  {"bls12381MapG2", "maps a field element to a point on the BLS12-381 G2 curve (EIP-2537)"},
+	0x100: {"p256Verify", "verifies a secp256r1 signature, the curve of passkeys and secure enclaves (EIP-7951)"},
+}
+
+func init() {
+	for n, p := range precompiles {
+		addr := common.BytesToAddress([]byte{byte(n >> 8), byte(n)})
+		knownAddresses[addr] = addressClass{
+			fmt.Sprintf("the %s precompile", p[0]),
+			fmt.Sprintf("A precompiled contract built into every client, it %s. Calling it costs a fixed or input based amount of gas and runs no EVM code", p[1]),
+		}
+	}
+}
+
+// addressInfo explains an address, in its EIP-55 checksummed form
+func addressInfo(label string, addr common.Address, verbose bool) (string, string) {
+	if class, ok := knownAddresses[addr]; ok {
+		return fmt.Sprintf("%s: %s (%s)", label, addr.Hex(), class.name), class.more
+	}
+	more := shortTo
+	if verbose {
+		more = verboseTo
+	}
+	return fmt.Sprintf("%s: %s", label, addr.Hex()), more
+}
+
+// checksumError tells whether the case of a hex address doesn't match its EIP-55 checksum. All lower
+// or all upper case addresses carry no checksum
+func checksumError(str string, addr common.Address) string {
+	str = strings.TrimPrefix(str, "0x")
+	if str == strings.ToLower(str) || str == strings.ToUpper(str) || str == addr.Hex()[2:] {
+		return ""
+	}
+	return fmt.Sprintf("WARNING: the case of the address doesn't match its EIP-55 checksum, it should be %s. A wrong checksum usually means a mistyped address", addr.Hex())
+}
+
+// addressHandler explains an address on its own, checking the checksum of its case
+func addressHandler(c echo.Context) error {
+	str := strings.TrimPrefix(c.Param("address"), "0x")
+	buf, err := hex.DecodeString(str)
+	if err != nil {
+		out, _ := json.MarshalIndent(Splain{Error: hexError(str, err)}, "", "	")
+		return c.JSONBlob(http.StatusBadRequest, out)
+	}
+	if len(buf) != common.AddressLength {
+		out, _ := json.MarshalIndent(Splain{Error: &ParseError{len(buf), fmt.Sprintf("an address is 20 bytes, not %d", len(buf))}}, "", "	")
+		return c.JSONBlob(http.StatusBadRequest, out)
+	}
+
+	addr := common.BytesToAddress(buf)
+	tok := Token{Hex: Hex(buf), Length: len(buf), Field: "address", Parent: -1}
+	tok.Text, tok.More = addressInfo("Address", addr, c.QueryParam("verbose") == "true")
+	if warning := checksumError(str, addr); warning != "" {
+		tok.More = warning + ". " + tok.More
+	}
+	out, _ := json.MarshalIndent(Splain{Tokens: []Token{tok}}, "", "	")
+	return c.String(http.StatusOK, string(out))
+}
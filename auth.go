@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// addAuthList adds nodes for the EIP-7702 authorization list and every field of each authorization
+func (s *Splain) addAuthList(list rlpItem, verbose bool) *ParseError {
+	if list.kind != rlp.List {
+		return &ParseError{s.pos, "Authorization List: expected a list"}
+	}
+	more := shortAuthList
+	if verbose {
+		more = verboseAuthList
+	}
+	s.addListPrefix(list, fmt.Sprintf("Authorization List: %d authorizations", countRLP(list.content)), more)
+
+	for content, i := list.content, 0; len(content) > 0; i++ {
+		tuple, next, err := splitRLP(content)
+		if err != nil || tuple.kind != rlp.List {
+			return &ParseError{s.pos, fmt.Sprintf("Authorization %d: expected a [chain_id, address, nonce, y_parity, r, s] list", i)}
+		}
+		fields, perr := splitAuth(tuple, s.pos+len(tuple.enc)-len(tuple.content), i)
+		if perr != nil {
+			return perr
+		}
+		chainID, address, nonce, v, r, sig := fields[0], fields[1], fields[2], fields[3], fields[4], fields[5]
+
+		var auth types.SetCodeAuthorization
+		auth.ChainID.SetBytes(chainID.content)
+		auth.Address = common.BytesToAddress(address.content)
+		auth.Nonce = new(big.Int).SetBytes(nonce.content).Uint64()
+		auth.V = uint8(new(big.Int).SetBytes(v.content).Uint64())
+		auth.R.SetBytes(r.content)
+		auth.S.SetBytes(sig.content)
+
+		txt, more := authInfo(i, auth)
+		s.addListPrefix(tuple, txt, more)
+
+		txt = fmt.Sprintf("Authorization Chain ID: %s", chainName(auth.ChainID.ToBig()))
+		more = "The chain this authorization is valid on."
+		if auth.ChainID.IsZero() {
+			more = "A chain ID of 0 means this authorization is valid on every chain. Anyone can replay it wherever the authority's nonce matches"
+		}
+		s.addValue(chainID, joinReasons(chainID.noncanon, intNonCanonical(chainID)), txt, more, verbose)
+
+		txt = fmt.Sprintf("Delegate Address: %s", auth.Address.Hex())
+		more = "The contract whose code the authority will run. Its account code is set to the delegation designator 0xef0100 followed by this address"
+		if auth.Address == (common.Address{}) {
+			more = "Delegating to the zero address clears any existing delegation and turns the authority back into a plain EOA"
+		}
+		s.addValue(address, address.noncanon, txt, more, verbose)
+
+		s.addValue(nonce, joinReasons(nonce.noncanon, intNonCanonical(nonce)),
+			fmt.Sprintf("Authorization Nonce: %d", auth.Nonce),
+			"Must equal the authority's current nonce when the authorization is processed, and increments it. If the authority also sent this transaction its nonce has already been bumped, so this must be the transaction nonce + 1",
+			verbose)
+
+		s.addValue(v, joinReasons(v.noncanon, intNonCanonical(v)), fmt.Sprintf("Authorization Y Parity: %d", auth.V), "The parity of the y component of the authorization signature's ephemeral public key", verbose)
+
+		s.addValue(r, joinReasons(r.noncanon, intNonCanonical(r)), fmt.Sprintf("Authorization Signature (r) value: %x", auth.R.Bytes()), "Part of the authority's signature over keccak256(0x05 || rlp([chain_id, address, nonce]))", verbose)
+
+		s.addValue(sig, joinReasons(sig.noncanon, intNonCanonical(sig)), fmt.Sprintf("Authorization Signature (s) value: %x", auth.S.Bytes()), "Part of the authority's signature over keccak256(0x05 || rlp([chain_id, address, nonce]))", verbose)
+
+		content = next
+	}
+	return nil
+}
+
+// authSizes are the largest sizes in bytes of the fields of an authorization tuple
+var authSizes = []int{32, common.AddressLength, 8, 1, 32, 32}
+
+// splitAuth splits an authorization tuple, whose fields start at offset off, into its six fields
+func splitAuth(tuple rlpItem, off, i int) ([]rlpItem, *ParseError) {
+	var fields []rlpItem
+	rest := tuple.content
+	for j, size := range authSizes {
+		it, next, err := splitRLP(rest)
+		if err != nil || it.kind == rlp.List || len(it.content) > size || (j == 1 && len(it.content) != size) {
+			return nil, &ParseError{off, fmt.Sprintf("Authorization %d: field %d of [chain_id, address, nonce, y_parity, r, s] is malformed", i, j)}
+		}
+		fields = append(fields, it)
+		off += len(it.enc)
+		rest = next
+	}
+	if len(rest) > 0 {
+		return nil, &ParseError{off, fmt.Sprintf("Authorization %d: unexpected bytes after the s value", i)}
+	}
+	return fields, nil
+}
+
+// authInfo names the EOA delegating its code and the contract it delegates to
+func authInfo(i int, auth types.SetCodeAuthorization) (string, string) {
+	authority, err := auth.Authority()
+	if err != nil {
+		txt := fmt.Sprintf("Authorization %d: INVALID signature, delegates to %s", i, auth.Address.Hex())
+		more := fmt.Sprintf("The authority could not be recovered from the signature (%v). Clients skip this authorization and the transaction continues without it", err)
+		return txt, more
+	}
+	if auth.Address == (common.Address{}) {
+		txt := fmt.Sprintf("Authorization %d: EOA %s clears its delegation", i, authority.Hex())
+		more := "The authority is recovered from the signature. This tuple resets the EOA's code to empty"
+		return txt, more
+	}
+	txt := fmt.Sprintf("Authorization %d: EOA %s delegates to the code of %s", i, authority.Hex(), auth.Address.Hex())
+	more := fmt.Sprintf("The authority %s is recovered from the signature. From now on any call to it runs the code of contract %s in the context of the EOA, with its balance and storage, until a later authorization changes it. The private key keeps full control of the account", authority.Hex(), auth.Address.Hex())
+	return txt, more
+}
+
+var shortAuthList = "Authorizations that set the code of EOAs to a delegation to contract code (EIP-7702)."
+var verboseAuthList = "Authorizations that set the code of EOAs to a delegation to contract code (EIP-7702). Each [chain_id, address, nonce, y_parity, r, s] tuple is signed by the EOA (the authority) itself, not by the transaction sender, so a relayer can submit delegations on behalf of other accounts. Each authorization costs 25000 gas, partly refunded if the account already exists"
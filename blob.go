@@ -0,0 +1,191 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// version byte of a versioned hash whose remaining 31 bytes are sha256(kzg commitment)[1:]
+const blobCommitmentVersionKZG = 0x01
+
+const fieldElementsPerBlob = 4096
+
+// blobNetworkForm tells whether a blob transaction payload is in the network encoding
+// rlp([tx_payload_body, blobs, commitments, proofs]) rather than the canonical rlp(tx_payload_body).
+// The first item of the network form is itself a list where the canonical form starts with the chain id
+func blobNetworkForm(payload []byte) bool {
+	wrapper, _, err := splitRLP(payload)
+	if err != nil || wrapper.kind != rlp.List {
+		return false
+	}
+	inner, _, err := splitRLP(wrapper.content)
+	return err == nil && inner.kind == rlp.List
+}
+
+// add a node for the network wrapper list prefix and return the inner tx_payload_body and the sidecar after it
+func (s *Splain) addBlobWrapper(payload []byte) ([]byte, []byte, *ParseError) {
+	wrapper, rest, _ := splitRLP(payload)
+	if len(rest) > 0 {
+		return nil, nil, &ParseError{s.pos + len(wrapper.enc), fmt.Sprintf("%d unexpected bytes after the end of the transaction", len(rest))}
+	}
+	s.addListPrefix(wrapper,
+		"RLP Prefix. Tells us that this is the network form of a blob transaction, a list of [transaction, blobs, commitments, proofs]",
+		"Blob transactions are gossiped between nodes together with their blobs, KZG commitments and proofs so every node can check the blobs are available. Only the inner transaction is included in a block, the blobs are kept by the consensus layer")
+	// blobNetworkForm already checked the inner transaction splits cleanly
+	inner, sidecar, _ := splitRLP(wrapper.content)
+	return inner.enc, sidecar, nil
+}
+
+func maxBlobFeeInfo(val interface{}, verbose bool) (string, string) {
+	buf, _ := val.([]byte)
+	i := new(big.Int).SetBytes(buf)
+
+	txt := fmt.Sprintf("Max Fee Per Blob Gas: %s", gweiAmount(i))
+	more := shortMaxBlobFee
+	if verbose {
+		more = verboseMaxBlobFee
+	}
+	return txt, more
+}
+
+var shortMaxBlobFee = "The maximum price (in wei) the sender is willing to pay per unit of blob gas."
+var verboseMaxBlobFee = "The maximum price (in wei) the sender is willing to pay per unit of blob gas. Blob gas has its own EIP-1559 style market with a separate blob base fee, which is burned in full. Every blob uses 131072 blob gas, independent of the gas limit"
+
+// addBlobHashes adds nodes for the blob_versioned_hashes list and each of its hashes
+func (s *Splain) addBlobHashes(list rlpItem, verbose bool) *ParseError {
+	if list.kind != rlp.List {
+		return &ParseError{s.pos, "Blob Versioned Hashes: expected a list"}
+	}
+	s.addListPrefix(list,
+		fmt.Sprintf("Blob Versioned Hashes: %d blobs", countRLP(list.content)),
+		"One hash for every blob carried by this transaction. The EVM can read them with the BLOBHASH opcode, while the blobs themselves are never visible to the EVM")
+
+	for content, i := list.content, 0; len(content) > 0; i++ {
+		it, next, err := splitRLP(content)
+		if err != nil || it.kind == rlp.List || len(it.content) != common.HashLength {
+			return &ParseError{s.pos, "Blob Versioned Hashes: expected a 32 byte hash"}
+		}
+		h := common.BytesToHash(it.content)
+		s.blobHashes = append(s.blobHashes, h)
+
+		txt := fmt.Sprintf("Blob Versioned Hash %d: 0x%x", i, h)
+		more := fmt.Sprintf("Version byte 0x%02x is unknown, only 0x01 (KZG) is valid", h[0])
+		if h[0] == blobCommitmentVersionKZG {
+			more = "Version byte 0x01 means KZG. The remaining 31 bytes are the sha256 hash of the blob's KZG commitment with its first byte dropped"
+		}
+		s.addValue(it, it.noncanon, txt, more, verbose)
+		content = next
+	}
+	return nil
+}
+
+// addBlobSidecar adds nodes for the optional version, blobs, commitments and proofs that follow
+// the transaction in the network form of a blob transaction
+func (s *Splain) addBlobSidecar(sidecar []byte, verbose bool) *ParseError {
+	it, rest, err := splitRLP(sidecar)
+	if err != nil {
+		return &ParseError{s.pos, fmt.Sprintf("blob sidecar: %v", err)}
+	}
+	if it.kind != rlp.List {
+		s.field = "sidecarVersion"
+		version := new(big.Int).SetBytes(it.content)
+		s.addValue(it, joinReasons(it.noncanon, intNonCanonical(it)),
+			fmt.Sprintf("Blob Sidecar Version: %s", version),
+			"Version 1 sidecars carry 128 cell proofs per blob for PeerDAS data availability sampling (EIP-7594) instead of a single proof per blob",
+			verbose)
+		if it, rest, err = splitRLP(rest); err != nil {
+			return &ParseError{s.pos, fmt.Sprintf("blob sidecar: %v", err)}
+		}
+	}
+
+	// blobs
+	s.field = "blobs"
+	if it.kind != rlp.List {
+		return &ParseError{s.pos, "blob sidecar: expected a list of blobs"}
+	}
+	s.addListPrefix(it, fmt.Sprintf("Blobs: %d blobs", countRLP(it.content)), "The blob data itself, 128KiB per blob")
+	for content, i := it.content, 0; len(content) > 0; i++ {
+		blob, next, err := splitRLP(content)
+		if err != nil || blob.kind == rlp.List || len(blob.content) != len(kzg4844.Blob{}) {
+			return &ParseError{s.pos, "blob sidecar: expected a 131072 byte blob"}
+		}
+		s.addBlob(blob, i)
+		content = next
+	}
+
+	// commitments
+	s.field = "commitments"
+	if it, rest, err = splitRLP(rest); err != nil || it.kind != rlp.List {
+		return &ParseError{s.pos, "blob sidecar: expected a list of KZG commitments"}
+	}
+	s.addListPrefix(it, fmt.Sprintf("KZG Commitments: %d commitments", countRLP(it.content)), "A 48 byte KZG polynomial commitment to each blob")
+	for content, i := it.content, 0; len(content) > 0; i++ {
+		c, next, err := splitRLP(content)
+		if err != nil || c.kind == rlp.List || len(c.content) != len(kzg4844.Commitment{}) {
+			return &ParseError{s.pos, "blob sidecar: expected a 48 byte KZG commitment"}
+		}
+		var commitment kzg4844.Commitment
+		copy(commitment[:], c.content)
+		more := "A KZG commitment to the polynomial whose evaluations are the blob's field elements. Hashing it with sha256 and replacing the first byte with the version 0x01 gives the blob versioned hash"
+		vh := common.Hash(kzg4844.CalcBlobHashV1(sha256.New(), &commitment))
+		if i >= len(s.blobHashes) || vh != s.blobHashes[i] {
+			more = fmt.Sprintf("WARNING: this commitment hashes to 0x%x which does not match the blob versioned hash in the transaction", vh)
+		}
+		s.addValue(c, c.noncanon, fmt.Sprintf("KZG Commitment %d: 0x%x", i, commitment), more, verbose)
+		content = next
+	}
+
+	// proofs
+	s.field = "proofs"
+	if it, rest, err = splitRLP(rest); err != nil || it.kind != rlp.List {
+		return &ParseError{s.pos, "blob sidecar: expected a list of KZG proofs"}
+	}
+	s.addListPrefix(it, fmt.Sprintf("KZG Proofs: %d proofs", countRLP(it.content)), "48 byte KZG proofs that the blobs match their commitments")
+	for content, i := it.content, 0; len(content) > 0; i++ {
+		p, next, err := splitRLP(content)
+		if err != nil || p.kind == rlp.List || len(p.content) != len(kzg4844.Proof{}) {
+			return &ParseError{s.pos, "blob sidecar: expected a 48 byte KZG proof"}
+		}
+		s.addValue(p, p.noncanon, fmt.Sprintf("KZG Proof %d: 0x%x", i, p.content), "Proves the blob data evaluates to the committed polynomial, so nodes can verify a blob against its commitment without trusting the sender", verbose)
+		content = next
+	}
+
+	if len(rest) > 0 {
+		return &ParseError{s.pos, fmt.Sprintf("%d unexpected bytes after the blob sidecar", len(rest))}
+	}
+	return nil
+}
+
+// add a node summarizing a blob. Rather than dumping all of its 128KiB the token only covers its
+// prefix and first field element, the rest of the blob is skipped
+func (s *Splain) addBlob(blob rlpItem, i int) {
+	used := 0
+	for j := 0; j < fieldElementsPerBlob; j++ {
+		if !isZero(blob.content[j*32 : (j+1)*32]) {
+			used = j + 1
+		}
+	}
+	shown := len(blob.enc) - len(blob.content) + 32
+
+	var tok Token
+	tok.Hex = Hex(blob.enc[:shown])
+	tok.Text = fmt.Sprintf("Blob %d: 128KiB, %d of %d field elements used", i, used, fieldElementsPerBlob)
+	tok.More = "A blob is 4096 field elements of 32 bytes each. Only the first field element is shown. Blobs are pruned by the consensus layer after about 18 days"
+	s.warnNonCanonical(&tok, s.pos, blob.noncanon)
+	s.addToken(tok, shown)
+	s.pos += len(blob.enc) - shown
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
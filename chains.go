@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// chainNames is a registry of well known EIP-155 chain IDs
+var chainNames = map[uint64]string{
+	1:        "Mainnet",
+	3:        "Ropsten",
+	4:        "Rinkeby",
+	5:        "Goerli",
+	10:       "OP Mainnet",
+	42:       "Kovan",
+	56:       "BNB Smart Chain",
+	61:       "Ethereum Classic",
+	100:      "Gnosis",
+	137:      "Polygon",
+	250:      "Fantom",
+	324:      "zkSync Era",
+	1337:     "Local Development",
+	8453:     "Base",
+	17000:    "Holesky",
+	31337:    "Hardhat/Anvil",
+	42161:    "Arbitrum One",
+	43114:    "Avalanche C-Chain",
+	59144:    "Linea",
+	534352:   "Scroll",
+	560048:   "Hoodi",
+	11155111: "Sepolia",
+}
+
+// chainName returns the chain ID followed by the name of the chain if we know it
+func chainName(id *big.Int) string {
+	if id.IsUint64() {
+		if name, ok := chainNames[id.Uint64()]; ok {
+			return fmt.Sprintf("%s (%s)", id, name)
+		}
+	}
+	return fmt.Sprintf("%s (unknown chain)", id)
+}
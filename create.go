@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// create2Factories are well known contracts that deploy the init code in their calldata with CREATE2.
+// The deterministic deployment proxies take the salt followed by the init code, with no selector
+var create2Factories = map[common.Address]string{
+	common.HexToAddress("0x4e59b44847b379578588920ca78fbf26c0b4956c"): "the deterministic deployment proxy",
+	common.HexToAddress("0x914d7fec6aac8cd542e72bca78b30650d45643d7"): "the Safe singleton factory",
+}
+
+// addContractAddressNode adds a derived node with the address of the contract a contract creation
+// deploys, right after the empty recipient
+func (s *Splain) addContractAddressNode(tx *types.Transaction, verbose bool) {
+	if tx.To() != nil {
+		return
+	}
+	sender, _, err := recoverSender(tx)
+	if err != nil {
+		return
+	}
+	preimage, _ := rlp.EncodeToBytes([]interface{}{sender, tx.Nonce()})
+
+	var tok Token
+	tok.Field = "contractAddress"
+	tok.Text = fmt.Sprintf("Contract Address: %s", crypto.CreateAddress(sender, tx.Nonce()).Hex())
+	tok.More = fmt.Sprintf("Derived, not part of the raw transaction. The address of the contract this transaction creates, the last 20 bytes of the Keccak-256 hash of the RLP list [sender, nonce]: 0x%x", preimage)
+	if verbose {
+		tok.More += ". It only depends on who deploys and their nonce, so the address is known before the transaction is mined"
+	}
+	// after the recipient's value, which follows its rlp prefix when verbose
+	for i := len(s.Tokens) - 1; i >= 0; i-- {
+		if s.Tokens[i].Field == fieldKeys[RECIPIENT] {
+			s.insertToken(i+1, tok)
+			return
+		}
+	}
+}
+
+// addCreate2Node adds a derived node with the address of the contract that data deploys when it is
+// sent to a known CREATE2 factory
+func (s *Splain) addCreate2Node(data []byte, verbose bool) {
+	if len(s.to) != common.AddressLength || len(data) <= 32 {
+		return
+	}
+	factory := common.BytesToAddress(s.to)
+	name, ok := create2Factories[factory]
+	if !ok {
+		return
+	}
+	salt, initCode := data[:32], data[32:]
+	codeHash := crypto.Keccak256(initCode)
+
+	var tok Token
+	tok.Field = "create2Address"
+	tok.Text = fmt.Sprintf("CREATE2 Address: %s", crypto.CreateAddress2(factory, [32]byte(salt), codeHash).Hex())
+	tok.More = fmt.Sprintf("Derived, not part of the raw transaction. The data is sent to %s, which deploys the init code after the 32 byte salt 0x%x with CREATE2. The new contract's address is the last 20 bytes of the Keccak-256 hash of 0xff ++ factory ++ salt ++ keccak256(init code): 0xff%x%x%x", name, salt, factory, salt, codeHash)
+	if verbose {
+		tok.More += ". Unlike CREATE the address doesn't depend on a nonce, the same init code and salt give the same address on every chain the factory is deployed at (EIP-1014)"
+	}
+	s.addToken(tok, 0)
+}
+
+// insertToken inserts a derived token before the token at i, keeping the Parent indexes of the
+// tokens after it right
+func (s *Splain) insertToken(i int, tok Token) {
+	prev := s.Tokens[i-1]
+	tok.Offset = prev.Offset + prev.Length
+	tok.Length = 0
+	tok.Parent = prev.Parent
+	s.Tokens = append(s.Tokens[:i], append([]Token{tok}, s.Tokens[i:]...)...)
+	for j := i + 1; j < len(s.Tokens); j++ {
+		if s.Tokens[j].Parent >= i {
+			s.Tokens[j].Parent++
+		}
+	}
+}
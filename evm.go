@@ -0,0 +1,331 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// opInfo describes an EVM opcode: how many stack items it takes and leaves, and what it does
+type opInfo struct {
+	name   string
+	pops   int
+	pushes int
+	desc   string
+}
+
+var opcodes = map[byte]opInfo{
+	0x00: {"STOP", 0, 0, "Halts execution successfully"},
+	0x01: {"ADD", 2, 1, "Addition modulo 2^256"},
+	0x02: {"MUL", 2, 1, "Multiplication modulo 2^256"},
+	0x03: {"SUB", 2, 1, "Subtraction modulo 2^256"},
+	0x04: {"DIV", 2, 1, "Unsigned integer division, 0 when dividing by 0"},
+	0x05: {"SDIV", 2, 1, "Signed integer division, 0 when dividing by 0"},
+	0x06: {"MOD", 2, 1, "Unsigned modulo, 0 when dividing by 0"},
+	0x07: {"SMOD", 2, 1, "Signed modulo, 0 when dividing by 0"},
+	0x08: {"ADDMOD", 3, 1, "Addition modulo a third value"},
+	0x09: {"MULMOD", 3, 1, "Multiplication modulo a third value"},
+	0x0a: {"EXP", 2, 1, "Exponentiation modulo 2^256"},
+	0x0b: {"SIGNEXTEND", 2, 1, "Extends the sign of a smaller two's complement integer to 256 bits"},
+	0x10: {"LT", 2, 1, "Unsigned less than, 1 if true and 0 if false"},
+	0x11: {"GT", 2, 1, "Unsigned greater than, 1 if true and 0 if false"},
+	0x12: {"SLT", 2, 1, "Signed less than, 1 if true and 0 if false"},
+	0x13: {"SGT", 2, 1, "Signed greater than, 1 if true and 0 if false"},
+	0x14: {"EQ", 2, 1, "Equality, 1 if true and 0 if false"},
+	0x15: {"ISZERO", 1, 1, "1 if the value is 0, 0 otherwise"},
+	0x16: {"AND", 2, 1, "Bitwise and"},
+	0x17: {"OR", 2, 1, "Bitwise or"},
+	0x18: {"XOR", 2, 1, "Bitwise exclusive or"},
+	0x19: {"NOT", 1, 1, "Bitwise not"},
+	0x1a: {"BYTE", 2, 1, "A single byte of a word, counted from the most significant"},
+	0x1b: {"SHL", 2, 1, "Shift left"},
+	0x1c: {"SHR", 2, 1, "Logical shift right"},
+	0x1d: {"SAR", 2, 1, "Arithmetic (signed) shift right"},
+	0x20: {"KECCAK256", 2, 1, "Keccak-256 hash of a range of memory"},
+	0x30: {"ADDRESS", 0, 1, "Address of the executing contract"},
+	0x31: {"BALANCE", 1, 1, "Balance of an account in wei"},
+	0x32: {"ORIGIN", 0, 1, "The transaction sender, the EOA that started the execution"},
+	0x33: {"CALLER", 0, 1, "The address that called the executing contract"},
+	0x34: {"CALLVALUE", 0, 1, "Wei sent with the call"},
+	0x35: {"CALLDATALOAD", 1, 1, "A word of the calldata"},
+	0x36: {"CALLDATASIZE", 0, 1, "Size of the calldata in bytes"},
+	0x37: {"CALLDATACOPY", 3, 0, "Copies calldata to memory"},
+	0x38: {"CODESIZE", 0, 1, "Size of the executing code in bytes. While deploying this is the size of the init code"},
+	0x39: {"CODECOPY", 3, 0, "Copies the executing code to memory. Takes the memory offset, the code offset and the size"},
+	0x3a: {"GASPRICE", 0, 1, "Gas price of the transaction"},
+	0x3b: {"EXTCODESIZE", 1, 1, "Size of the code of an account"},
+	0x3c: {"EXTCODECOPY", 4, 0, "Copies the code of an account to memory"},
+	0x3d: {"RETURNDATASIZE", 0, 1, "Size of the data returned by the last call"},
+	0x3e: {"RETURNDATACOPY", 3, 0, "Copies the data returned by the last call to memory"},
+	0x3f: {"EXTCODEHASH", 1, 1, "Keccak-256 hash of the code of an account"},
+	0x40: {"BLOCKHASH", 1, 1, "Hash of one of the 256 most recent blocks"},
+	0x41: {"COINBASE", 0, 1, "Fee recipient of the current block"},
+	0x42: {"TIMESTAMP", 0, 1, "Timestamp of the current block"},
+	0x43: {"NUMBER", 0, 1, "Number of the current block"},
+	0x44: {"PREVRANDAO", 0, 1, "Randomness from the beacon chain, DIFFICULTY before the merge"},
+	0x45: {"GASLIMIT", 0, 1, "Gas limit of the current block"},
+	0x46: {"CHAINID", 0, 1, "The chain ID (EIP-1344)"},
+	0x47: {"SELFBALANCE", 0, 1, "Balance of the executing contract"},
+	0x48: {"BASEFEE", 0, 1, "Base fee of the current block (EIP-3198)"},
+	0x49: {"BLOBHASH", 1, 1, "A versioned hash of the blobs of the transaction (EIP-4844)"},
+	0x4a: {"BLOBBASEFEE", 0, 1, "Blob base fee of the current block (EIP-7516)"},
+	0x50: {"POP", 1, 0, "Removes the top of the stack"},
+	0x51: {"MLOAD", 1, 1, "Loads a word from memory"},
+	0x52: {"MSTORE", 2, 0, "Stores a word to memory"},
+	0x53: {"MSTORE8", 2, 0, "Stores a byte to memory"},
+	0x54: {"SLOAD", 1, 1, "Loads a word from storage"},
+	0x55: {"SSTORE", 2, 0, "Stores a word to storage"},
+	0x56: {"JUMP", 1, 0, "Jumps to a JUMPDEST"},
+	0x57: {"JUMPI", 2, 0, "Jumps to a JUMPDEST if the condition is not 0"},
+	0x58: {"PC", 0, 1, "Offset of this instruction in the code"},
+	0x59: {"MSIZE", 0, 1, "Size of the memory used so far in bytes"},
+	0x5a: {"GAS", 0, 1, "Gas left"},
+	0x5b: {"JUMPDEST", 0, 0, "Marks a valid jump destination"},
+	0x5c: {"TLOAD", 1, 1, "Loads a word from transient storage (EIP-1153)"},
+	0x5d: {"TSTORE", 2, 0, "Stores a word to transient storage (EIP-1153)"},
+	0x5e: {"MCOPY", 3, 0, "Copies memory to memory (EIP-5656)"},
+	0x5f: {"PUSH0", 0, 1, "Pushes 0 (EIP-3855)"},
+	0xf0: {"CREATE", 3, 1, "Creates a contract from init code in memory"},
+	0xf1: {"CALL", 7, 1, "Calls an account"},
+	0xf2: {"CALLCODE", 7, 1, "Calls the code of an account in the context of the executing contract, deprecated"},
+	0xf3: {"RETURN", 2, 0, "Halts execution returning a range of memory"},
+	0xf4: {"DELEGATECALL", 6, 1, "Calls the code of an account in the context of the executing contract, keeping caller and value"},
+	0xf5: {"CREATE2", 4, 1, "Creates a contract from init code in memory at an address derived from a salt (EIP-1014)"},
+	0xfa: {"STATICCALL", 6, 1, "Calls an account without allowing it to change state"},
+	0xfd: {"REVERT", 2, 0, "Halts execution reverting state changes, returning a range of memory"},
+	0xfe: {"INVALID", 0, 0, "Designated invalid instruction, consumes all gas. Solidity also uses it to separate code from data"},
+	0xff: {"SELFDESTRUCT", 1, 0, "Sends the balance of the contract to an address. Since EIP-6780 it only deletes contracts created in the same transaction"},
+}
+
+func init() {
+	for i := 1; i <= 32; i++ {
+		opcodes[byte(0x5f+i)] = opInfo{fmt.Sprintf("PUSH%d", i), 0, 1, fmt.Sprintf("Pushes the next %d bytes of code", i)}
+	}
+	for i := 1; i <= 16; i++ {
+		opcodes[byte(0x7f+i)] = opInfo{fmt.Sprintf("DUP%d", i), i, i + 1, fmt.Sprintf("Duplicates stack item %d", i)}
+		opcodes[byte(0x8f+i)] = opInfo{fmt.Sprintf("SWAP%d", i), i + 1, i + 1, fmt.Sprintf("Swaps the top of the stack with stack item %d", i+1)}
+	}
+	for i := 0; i <= 4; i++ {
+		opcodes[byte(0xa0+i)] = opInfo{fmt.Sprintf("LOG%d", i), 2 + i, 0, fmt.Sprintf("Emits a log with %d topics", i)}
+	}
+}
+
+// instruction is an opcode at off in the code and its PUSH operand
+type instruction struct {
+	off     int
+	op      byte
+	operand []byte
+}
+
+// disassemble splits code into instructions. A PUSH at the end of the code can have fewer operand
+// bytes than it needs, the EVM reads them as zeros
+func disassemble(code []byte) []instruction {
+	var ins []instruction
+	for pc := 0; pc < len(code); {
+		in := instruction{off: pc, op: code[pc]}
+		pc++
+		if in.op >= 0x60 && in.op <= 0x7f {
+			end := pc + int(in.op-0x5f)
+			if end > len(code) {
+				end = len(code)
+			}
+			in.operand = code[pc:end]
+			pc = end
+		}
+		ins = append(ins, in)
+	}
+	return ins
+}
+
+// runtimeCode finds the code init code deploys. Solidity constructors end by copying the runtime
+// code from the init code to memory with CODECOPY and returning it with RETURN. Following the
+// constants on the stack through the constructor finds the offset and size of the runtime code.
+// ok is false if they can't be followed
+func runtimeCode(ins []instruction, size int) (copyAt, returnAt, off, n int, ok bool) {
+	var stack []*big.Int
+	pop := func() *big.Int {
+		if len(stack) == 0 {
+			return nil
+		}
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return v
+	}
+	copyAt = -1
+	for i, in := range ins {
+		op := opcodes[in.op]
+		switch {
+		case in.op >= 0x5f && in.op <= 0x7f:
+			stack = append(stack, new(big.Int).SetBytes(in.operand))
+		case in.op >= 0x80 && in.op <= 0x8f:
+			var v *big.Int
+			if d := int(in.op - 0x7f); d <= len(stack) {
+				v = stack[len(stack)-d]
+			}
+			stack = append(stack, v)
+		case in.op >= 0x90 && in.op <= 0x9f:
+			if d := int(in.op - 0x8f); d < len(stack) {
+				top := len(stack) - 1
+				stack[top], stack[top-d] = stack[top-d], stack[top]
+			}
+		case in.op == 0x39: // CODECOPY
+			pop()
+			from, length := pop(), pop()
+			if from != nil && length != nil && from.IsInt64() && length.IsInt64() {
+				copyAt, off, n = i, int(from.Int64()), int(length.Int64())
+			}
+		case in.op == 0xf3: // RETURN
+			// the runtime code follows the constructor
+			if copyAt >= 0 && n > 0 && off > in.off && off+n <= size {
+				return copyAt, i, off, n, true
+			}
+			return 0, 0, 0, 0, false
+		default:
+			for j := 0; j < op.pops; j++ {
+				pop()
+			}
+			for j := 0; j < op.pushes; j++ {
+				stack = append(stack, nil)
+			}
+		}
+	}
+	return 0, 0, 0, 0, false
+}
+
+// opToken explains an instruction of code, a slice of the raw input at base
+func opToken(code []byte, in instruction, base int) Token {
+	op, known := opcodes[in.op]
+	tok := Token{
+		Hex:    Hex(code[in.off : in.off+1+len(in.operand)]),
+		Text:   op.name,
+		More:   op.desc,
+		Offset: base + in.off,
+		Length: 1 + len(in.operand),
+	}
+	if !known {
+		tok.Text = fmt.Sprintf("0x%02x", in.op)
+		tok.More = "Not an opcode, executing it fails like INVALID. Often data that follows the code, like the metadata Solidity appends"
+	}
+	if in.operand != nil {
+		tok.Text = fmt.Sprintf("%s 0x%x", op.name, in.operand)
+		if want := int(in.op - 0x5f); len(in.operand) < want {
+			tok.More = fmt.Sprintf("%s. The code ends %d bytes into the %d byte operand, the rest reads as zeros", op.desc, len(in.operand), want)
+		}
+	}
+	return tok
+}
+
+// addInitCode adds the data of a contract creation transaction: the constructor instruction by
+// instruction, the runtime code the constructor deploys and the constructor arguments appended to it
+func (s *Splain) addInitCode(it rlpItem, noncanon string, verbose bool) {
+	code := it.content
+	ins := disassemble(code)
+	copyAt, returnAt, off, n, ok := runtimeCode(ins, len(code))
+	constructor := len(code)
+	if ok {
+		constructor = off
+	}
+
+	// the rlp prefix gets its own node when verbose, otherwise it goes with the first instruction
+	start := s.pos
+	i := len(it.enc) - len(code)
+	if verbose {
+		addRLPNode(s, it.enc)
+	}
+	first := true
+	for j, in := range ins {
+		if in.off >= constructor {
+			break
+		}
+		tok := opToken(code, in, s.pos)
+		switch {
+		case ok && j == copyAt:
+			tok.Text = fmt.Sprintf("%s: copies the %d byte runtime code at offset %d of the init code to memory", tok.Text, n, off)
+			tok.More = "The end of the constructor. " + tok.More
+		case ok && j == returnAt:
+			tok.Text = fmt.Sprintf("%s: returns the runtime code, the code of the new contract", tok.Text)
+			tok.More = "Whatever the init code returns is stored as the code of the new contract. " + tok.More
+		}
+		// PUSH operands can run into the runtime code, stop the constructor at the boundary
+		length := tok.Length
+		if in.off+length > constructor {
+			length = constructor - in.off
+			tok.Hex = Hex(code[in.off:constructor])
+		}
+		if first {
+			tok.Hex = Hex(it.enc[s.pos-start:i]) + tok.Hex
+			length += i - (s.pos - start)
+			tok.More = fmt.Sprintf("%s. %s", tok.More, shortInitCode)
+			if verbose {
+				tok.More = fmt.Sprintf("%s. %s", tok.More, verboseInitCode)
+			}
+			s.warnNonCanonical(&tok, start, noncanon)
+			first = false
+		}
+		s.addToken(tok, length)
+	}
+	if !ok {
+		return
+	}
+
+	// the runtime code is one node, with its instructions as children when verbose
+	runtime := code[off : off+n]
+	tok := Token{
+		Hex:  Hex(runtime),
+		Text: fmt.Sprintf("Runtime Code: %d bytes", n),
+		More: "The code of the new contract. The constructor copies it to memory and returns it, it doesn't run while deploying",
+	}
+	meta, summary, end := metadataTokens(runtime, s.pos)
+	if summary != "" {
+		tok.Text += ", " + summary
+	}
+	if verbose {
+		tok.More += ". Its instructions are the children of this node"
+		for _, in := range disassemble(runtime[:end]) {
+			child := opToken(runtime, in, s.pos)
+			child.Parent, child.Depth = -1, 1
+			tok.Children = append(tok.Children, child)
+		}
+	}
+	tok.Children = append(tok.Children, meta...)
+	for i := range tok.Children {
+		tok.Children[i].Field = s.field
+	}
+	s.addToken(tok, n)
+
+	if args := code[off+n:]; len(args) > 0 {
+		s.addConstructorArgs(args)
+	}
+}
+
+// addConstructorArgs adds the arguments appended to the init code, decoded with the constructor
+// of the uploaded ABI if there is one, else a node for every word
+func (s *Splain) addConstructorArgs(args []byte) {
+	if s.abi != nil && len(s.abi.Constructor.Inputs) > 0 {
+		w := abiWalker{data: args, limit: maxABIParts}
+		if parts, err := w.walk(s.abi.Constructor.Inputs); err == nil {
+			for _, p := range parts {
+				s.addToken(Token{Hex: Hex(args[p.off : p.off+p.n]), Text: "Constructor Argument " + p.txt, More: p.more}, p.n)
+			}
+			return
+		}
+	}
+	if len(args)%32 != 0 {
+		s.addToken(Token{Hex: Hex(args), Text: fmt.Sprintf("Constructor Arguments: 0x%x", args), More: shortConstructorArgs}, len(args))
+		return
+	}
+	for i := 0; i < len(args); i += 32 {
+		word := args[i : i+32]
+		txt := fmt.Sprintf("Constructor Argument %d: 0x%x", i/32, word)
+		// a word that is a left padded 20 byte value is probably an address
+		if isZero(word[:12]) && !isZero(word[12:16]) {
+			txt = fmt.Sprintf("Constructor Argument %d: %s", i/32, common.BytesToAddress(word).Hex())
+		}
+		s.addToken(Token{Hex: Hex(word), Text: txt, More: shortConstructorArgs}, 32)
+	}
+}
+
+var shortInitCode = "This is a contract creation, the data is the init code. The EVM runs it once and stores what it returns as the code of the new contract"
+var verboseInitCode = "Solidity init code starts with the constructor, followed by the runtime code the constructor returns and the ABI encoded constructor arguments, which the constructor reads with CODECOPY"
+var shortConstructorArgs = "The ABI encoded constructor arguments, appended to the init code by the deployer. Upload the ABI with the request to decode them"
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// intrinsic gas costs, charged before any code runs
+const (
+	txBaseCost          = 21000 // every transaction
+	txCreateCost        = 32000 // on top of the base cost for contract creations
+	txDataZeroCost      = 4     // per zero byte of data
+	txDataNonZeroCost   = 16    // per non-zero byte of data (EIP-2028)
+	initCodeWordCost    = 2     // per 32 byte word of init code (EIP-3860)
+	authorizationCost   = 25000 // per authorization (EIP-7702)
+	txFloorPerTokenCost = 10    // minimum per data token, 4 tokens per non-zero byte and 1 per zero byte (EIP-7623)
+)
+
+// baseFee is the block base fee (in wei) to price transactions at when a request doesn't supply one,
+// from the -base-fee flag
+var baseFee *big.Int
+
+// parseBaseFee reads a base fee as a whole number of wei, or of gwei with a "gwei" suffix like "12.5gwei"
+func parseBaseFee(str string) (*big.Int, error) {
+	num := strings.TrimSpace(str)
+	gwei := strings.HasSuffix(num, "gwei")
+	if gwei {
+		num = strings.TrimSpace(strings.TrimSuffix(num, "gwei"))
+	}
+	fee, ok := new(big.Rat).SetString(num)
+	if gwei && ok {
+		fee.Mul(fee, big.NewRat(1e9, 1))
+	}
+	if !ok || !fee.IsInt() || fee.Sign() < 0 {
+		return nil, fmt.Errorf("%q is not a whole number of wei", str)
+	}
+	return fee.Num(), nil
+}
+
+// intrinsicGas is the gas a transaction costs before executing anything, with an explanation of
+// where it comes from. floor is the EIP-7623 minimum for transactions that are mostly data: the gas
+// used is raised to it, so the gas limit must cover it too
+func intrinsicGas(tx *types.Transaction) (gas, floor uint64, parts []string) {
+	data := tx.Data()
+	var zeros uint64
+	for _, b := range data {
+		if b == 0 {
+			zeros++
+		}
+	}
+	nonZeros := uint64(len(data)) - zeros
+
+	gas = txBaseCost
+	parts = append(parts, fmt.Sprintf("%d base", txBaseCost))
+	if tx.To() == nil {
+		words := (uint64(len(data)) + 31) / 32
+		gas += txCreateCost + words*initCodeWordCost
+		parts = append(parts, fmt.Sprintf("%d for creating a contract", txCreateCost), fmt.Sprintf("%d × %d words of init code", initCodeWordCost, words))
+	}
+	if len(data) > 0 {
+		gas += nonZeros*txDataNonZeroCost + zeros*txDataZeroCost
+		parts = append(parts, fmt.Sprintf("%d × %d non-zero data bytes", txDataNonZeroCost, nonZeros), fmt.Sprintf("%d × %d zero data bytes", txDataZeroCost, zeros))
+	}
+	if al := tx.AccessList(); len(al) > 0 {
+		keys := uint64(al.StorageKeys())
+		gas += uint64(len(al))*accessListAddressCost + keys*accessListStorageKeyCost
+		parts = append(parts, fmt.Sprintf("%d × %d access list addresses", accessListAddressCost, len(al)), fmt.Sprintf("%d × %d access list storage keys", accessListStorageKeyCost, keys))
+	}
+	if auths := tx.SetCodeAuthorizations(); len(auths) > 0 {
+		gas += uint64(len(auths)) * authorizationCost
+		parts = append(parts, fmt.Sprintf("%d × %d authorizations", authorizationCost, len(auths)))
+	}
+	floor = txBaseCost + (4*nonZeros+zeros)*txFloorPerTokenCost
+	return gas, floor, parts
+}
+
+// maxFee is the most the transaction can pay for gas: all of its gas limit at its highest gas
+// price, plus all of its blob gas at the highest blob gas price
+func maxFee(tx *types.Transaction) (fee, blobFee *big.Int) {
+	fee = new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap())
+	blobFee = new(big.Int)
+	if tx.Type() == types.BlobTxType {
+		blobFee.Mul(new(big.Int).SetUint64(tx.BlobGas()), tx.BlobGasFeeCap())
+	}
+	return fee, blobFee
+}
+
+// add derived nodes for the intrinsic gas, the maximum fee and the maximum cost of the transaction
+func (s *Splain) addGasNodes(tx *types.Transaction, verbose bool) {
+	gas, floor, parts := intrinsicGas(tx)
+	tok := Token{Field: "intrinsicGas"}
+	tok.Text = fmt.Sprintf("Intrinsic Gas: %d", gas)
+	tok.More = fmt.Sprintf("Derived, not part of the raw transaction. The gas charged before any code runs: %s", strings.Join(parts, " + "))
+	if floor > gas {
+		tok.More += fmt.Sprintf(". Transactions that are mostly data pay at least %d gas since EIP-7623 (21000 + 10 per data token, a non-zero byte is 4 tokens and a zero byte 1), the gas limit must cover that too", floor)
+	}
+	if need := max(gas, floor); tx.Gas() < need {
+		tok.More = fmt.Sprintf("WARNING: the gas limit %d is below the %d gas the transaction costs before running anything, it can never be mined. %s", tx.Gas(), need, tok.More)
+	}
+	if verbose {
+		tok.More += ". " + verboseIntrinsicGas
+	}
+	s.addToken(tok, 0)
+
+	fee, blobFee := maxFee(tx)
+	price := "Gas Price"
+	if tx.Type() >= types.DynamicFeeTxType {
+		price = "Max Fee Per Gas"
+	}
+	tok = Token{Field: "maxFee"}
+	tok.Text = fmt.Sprintf("Max Fee: %s", etherAmount(new(big.Int).Add(fee, blobFee)))
+	tok.More = fmt.Sprintf("Derived, not part of the raw transaction. The most the transaction can pay for gas, the Gas Limit %d × the %s %s", tx.Gas(), price, gweiAmount(tx.GasFeeCap()))
+	if tx.Type() == types.BlobTxType {
+		tok.More += fmt.Sprintf(", plus %d blob gas (131072 per blob) × the Max Fee Per Blob Gas %s", tx.BlobGas(), gweiAmount(tx.BlobGasFeeCap()))
+	}
+	if tx.Type() >= types.DynamicFeeTxType {
+		tok.More += ". The fee actually paid depends on the base fee of the block it is included in and is usually lower"
+	}
+	s.addToken(tok, 0)
+
+	tok = Token{Field: "maxCost"}
+	total := new(big.Int).Add(new(big.Int).Add(fee, blobFee), tx.Value())
+	tok.Text = fmt.Sprintf("Max Total Cost: %s", etherAmount(total))
+	tok.More = "Derived, not part of the raw transaction. The maximum fee plus the value sent. Nodes only accept the transaction if the sender's balance covers all of it"
+	s.addToken(tok, 0)
+}
+
+// add derived nodes for what the transaction pays at the base fee of a block: the effective gas
+// price, the tip the block producer receives and the fee burned. A transaction whose max fee is below
+// the base fee can't be included at all
+func (s *Splain) addBaseFeeNodes(tx *types.Transaction, verbose bool) {
+	if s.baseFee == nil {
+		return
+	}
+	tok := Token{Field: "baseFee"}
+	tok.Text = fmt.Sprintf("Base Fee: %s", gweiAmount(s.baseFee))
+	tok.More = "Supplied with the request, not part of the raw transaction. The base fee of the block the transaction is priced for"
+	tip, err := tx.EffectiveGasTip(s.baseFee)
+	if err != nil {
+		tok.More = fmt.Sprintf("WARNING: the transaction can't be included in a block with this base fee, it pays at most %s per gas. It stays pending until the base fee drops to that or the sender replaces it with a higher fee. %s", gweiAmount(tx.GasFeeCap()), tok.More)
+		s.addToken(tok, 0)
+		return
+	}
+	tok.More += ", the transaction can be included at it"
+	if verbose {
+		tok.More += ". " + verboseBaseFee
+	}
+	s.addToken(tok, 0)
+
+	price := new(big.Int).Add(s.baseFee, tip)
+	tok = Token{Field: "effectiveGasPrice"}
+	tok.Text = fmt.Sprintf("Effective Gas Price: %s", gweiAmount(price))
+	tok.More = "Derived, not part of the raw transaction. The price actually paid per gas at this base fee, the base fee plus the priority fee"
+	if tx.Type() >= types.DynamicFeeTxType {
+		tok.More += fmt.Sprintf(", capped at the Max Fee Per Gas %s", gweiAmount(tx.GasFeeCap()))
+	}
+	s.addToken(tok, 0)
+
+	tok = Token{Field: "effectivePriorityFee"}
+	tok.Text = fmt.Sprintf("Priority Fee Paid: %s", gweiAmount(tip))
+	tok.More = fmt.Sprintf("Derived, not part of the raw transaction. The tip per gas the block producer receives, at most %s if all the gas is used", etherAmount(new(big.Int).Mul(tip, new(big.Int).SetUint64(tx.Gas()))))
+	if tx.Type() >= types.DynamicFeeTxType && tip.Cmp(tx.GasTipCap()) < 0 {
+		tok.More += fmt.Sprintf(". It is less than the Max Priority Fee Per Gas %s, which would take the price above the max fee", gweiAmount(tx.GasTipCap()))
+	}
+	if tip.Sign() == 0 {
+		tok.More += ". With no tip block producers have no reason to include the transaction, and many won't"
+	}
+	s.addToken(tok, 0)
+
+	tok = Token{Field: "burnedFee"}
+	tok.Text = fmt.Sprintf("Burned Fee: %s", etherAmount(new(big.Int).Mul(s.baseFee, new(big.Int).SetUint64(tx.Gas()))))
+	tok.More = fmt.Sprintf("Derived, not part of the raw transaction. The most that is burned if all %d gas is used, the base fee × the Gas Limit. The base fee is destroyed rather than paid to anyone (EIP-1559)", tx.Gas())
+	s.addToken(tok, 0)
+}
+
+var verboseBaseFee = "Every block since the London upgrade has a base fee per gas set by the protocol, rising when the block before it was more than half full and falling when it was less. A transaction has to pay it in full to be included, whatever its type, and the base fee part of the price is burned while the rest goes to the block producer"
+
+var verboseIntrinsicGas = "Every transaction pays for the work of validating and storing it even if it does nothing: 21000 gas for the signature check and balance updates, 32000 more to create a contract, 16 gas per non-zero and 4 per zero byte of data (EIP-2028), 2 gas per 32 byte word of init code (EIP-3860), 2400 gas per access list address and 1900 per storage key (EIP-2930) and 25000 gas per authorization (EIP-7702). A transaction with a gas limit below it is invalid"
@@ -1,15 +1,16 @@
 package main
 
 import (
-	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"flag"
 	"fmt"
-	"log"
 	"math/big"
 	"net/http"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/labstack/echo"
@@ -18,6 +19,34 @@ import (
 // Splain contains all of the parsed tokens in the transaction
 type Splain struct {
 	Tokens []Token
+	Error  *ParseError `json:",omitempty"`
+
+	pos        int           // offset in the raw input of the next token
+	field      string        // the field the next tokens belong to
+	lists      []span        // the rlp lists the next token is inside of, innermost last
+	noncanon   *ParseError   // the first non-canonical encoding found
+	blobHashes []common.Hash // kept to check the commitments of a blob sidecar
+	to         []byte        // the recipient, kept to find the ABI for the data
+	abi        *abi.ABI      // the contract ABI uploaded with the request, if any
+	baseFee    *big.Int      // the block base fee to price the transaction at, if any
+	abiParts   int           // the ABI parts decoded so far, counting every nested call
+	abiErr     error         // the first ABI file in abiDir that failed to load
+}
+
+// span is an rlp list prefix token and the offset where its list ends
+type span struct {
+	index int
+	end   int
+}
+
+// ParseError tells where in the raw transaction parsing stopped and why
+type ParseError struct {
+	Offset int
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("byte %d: %s", e.Offset, e.Reason)
 }
 
 // Token contains all the visible fields for each token
@@ -25,6 +54,22 @@ type Token struct {
 	Hex  string
 	Text string
 	More string
+
+	// where the token is in the raw input. Derived tokens that are computed rather than read
+	// from the input have a length of 0 and sit right after the tokens they are derived from: the
+	// contract address after the recipient, the CREATE2 address after the data and the hashes,
+	// sender and fees after the last field. A blob's token only covers its prefix and first field
+	// element, the next token starts after the whole blob
+	Offset int
+	Length int
+	// the transaction field the token belongs to, e.g. "nonce" or "accessList"
+	Field string
+	// index in Splain.Tokens of the rlp list prefix token this token is inside of, -1 if none
+	Parent int
+
+	// nesting of tokens inside rlp lists, used by the generic rlp explainer
+	Depth    int     `json:",omitempty"`
+	Children []Token `json:",omitempty"`
 }
 
 type field int
@@ -39,101 +84,446 @@ var (
 	SIG_V     field = 6
 	SIG_R     field = 7
 	SIG_S     field = 8
+
+	// fields only found in EIP-2718 typed transactions
+	CHAIN_ID         field = 9
+	MAX_PRIORITY_FEE field = 10
+	MAX_FEE          field = 11
+	ACCESS_LIST      field = 12
+	SIG_Y_PARITY     field = 13
+	MAX_BLOB_FEE     field = 14
+	BLOB_HASHES      field = 15
+	AUTH_LIST        field = 16
 )
 
+var fieldNames = map[field]string{
+	NONCE:            "Nonce",
+	GAS_PRICE:        "Gas Price",
+	GAS_LIMIT:        "Gas Limit",
+	RECIPIENT:        "Recipient Address",
+	VALUE:            "Value",
+	DATA:             "Data",
+	SIG_V:            "Signature Prefix Value (v)",
+	SIG_R:            "Signature (r) value",
+	SIG_S:            "Signature (s) value",
+	CHAIN_ID:         "Chain ID",
+	MAX_PRIORITY_FEE: "Max Priority Fee Per Gas",
+	MAX_FEE:          "Max Fee Per Gas",
+	ACCESS_LIST:      "Access List",
+	SIG_Y_PARITY:     "Signature Y Parity",
+	MAX_BLOB_FEE:     "Max Fee Per Blob Gas",
+	BLOB_HASHES:      "Blob Versioned Hashes",
+	AUTH_LIST:        "Authorization List",
+}
+
+// fieldKeys identify the fields in Token.Field, using the names of the json rpc api
+var fieldKeys = map[field]string{
+	NONCE:            "nonce",
+	GAS_PRICE:        "gasPrice",
+	GAS_LIMIT:        "gas",
+	RECIPIENT:        "to",
+	VALUE:            "value",
+	DATA:             "input",
+	SIG_V:            "v",
+	SIG_R:            "r",
+	SIG_S:            "s",
+	CHAIN_ID:         "chainId",
+	MAX_PRIORITY_FEE: "maxPriorityFeePerGas",
+	MAX_FEE:          "maxFeePerGas",
+	ACCESS_LIST:      "accessList",
+	SIG_Y_PARITY:     "yParity",
+	MAX_BLOB_FEE:     "maxFeePerBlobGas",
+	BLOB_HASHES:      "blobVersionedHashes",
+	AUTH_LIST:        "authorizationList",
+}
+
+func (f field) String() string {
+	return fieldNames[f]
+}
+
+// txLayouts lists the fields of each transaction type in the order they are RLP encoded
+var txLayouts = map[uint8][]field{
+	types.LegacyTxType:     {NONCE, GAS_PRICE, GAS_LIMIT, RECIPIENT, VALUE, DATA, SIG_V, SIG_R, SIG_S},
+	types.AccessListTxType: {CHAIN_ID, NONCE, GAS_PRICE, GAS_LIMIT, RECIPIENT, VALUE, DATA, ACCESS_LIST, SIG_Y_PARITY, SIG_R, SIG_S},
+	types.DynamicFeeTxType: {CHAIN_ID, NONCE, MAX_PRIORITY_FEE, MAX_FEE, GAS_LIMIT, RECIPIENT, VALUE, DATA, ACCESS_LIST, SIG_Y_PARITY, SIG_R, SIG_S},
+	types.BlobTxType:       {CHAIN_ID, NONCE, MAX_PRIORITY_FEE, MAX_FEE, GAS_LIMIT, RECIPIENT, VALUE, DATA, ACCESS_LIST, MAX_BLOB_FEE, BLOB_HASHES, SIG_Y_PARITY, SIG_R, SIG_S},
+	types.SetCodeTxType:    {CHAIN_ID, NONCE, MAX_PRIORITY_FEE, MAX_FEE, GAS_LIMIT, RECIPIENT, VALUE, DATA, ACCESS_LIST, AUTH_LIST, SIG_Y_PARITY, SIG_R, SIG_S},
+}
+
+var txTypeNames = map[uint8]string{
+	types.AccessListTxType: "EIP-2930 access list",
+	types.DynamicFeeTxType: "EIP-1559 dynamic fee",
+	types.BlobTxType:       "EIP-4844 blob",
+	types.SetCodeTxType:    "EIP-7702 set code",
+}
+
 func main() {
+	flag.StringVar(&abiDir, "abi-dir", abiDir, "directory of contract ABIs named by address, e.g. 0x6b175474e89094c44da98b954eedeac495271d0f.json")
+	flag.IntVar(&maxCallDepth, "call-depth", maxCallDepth, "how deep to decode calls passed as bytes arguments of other calls, like multicalls")
+	priceFile := flag.String("prices", "", "json file of the price of one ether in fiat currencies, e.g. {\"USD\": \"2500.12\"}")
+	baseFeeFlag := flag.String("base-fee", "", "block base fee to price transactions at when a request has no basefee parameter, in wei or with a gwei suffix")
+	selectorFile := flag.String("selectors", "", "file of extra \"0xselector signature\" lines for the function selector database")
+	flag.Parse()
+
 	// start simple server
 	e := echo.New()
+	if *selectorFile != "" {
+		if err := loadSelectorFile(*selectorFile); err != nil {
+			e.Logger.Fatal(err)
+		}
+	}
+	if *priceFile != "" {
+		if err := loadPrices(*priceFile); err != nil {
+			e.Logger.Fatal(err)
+		}
+	}
+	if *baseFeeFlag != "" {
+		fee, err := parseBaseFee(*baseFeeFlag)
+		if err != nil {
+			e.Logger.Fatal(err)
+		}
+		baseFee = fee
+	}
 	e.GET("/", func(c echo.Context) error {
-		return c.String(http.StatusOK, string(parse(data, false)))
+		out, _ := parse(data, false)
+		return c.String(http.StatusOK, string(out))
 	})
 
-	e.GET("/:tx", func(c echo.Context) error {
-		rawTx := c.Param("tx")
-		verbose := c.QueryParam("verbose")
-		//fmt.Println("verbose", verbose)
-		v := false
-		if verbose == "true" {
-			v = true
-		}
+	e.GET("/:tx", txHandler)
+	e.POST("/:tx", txHandler)
+	e.GET("/rlp/:rlp", rlpHandler)
+	e.GET("/address/:address", addressHandler)
+	e.Logger.Fatal(e.Start(":8080"))
+}
+
+func txHandler(c echo.Context) error {
+	rawTx := c.Param("tx")
+	verbose := c.QueryParam("verbose")
+	//fmt.Println("verbose", verbose)
+	v := false
+	if verbose == "true" {
+		v = true
+	}
+
+	// fetch rawTx from etherscan if it looks like we have a tx hash instead of a raw tx
+	if len(rawTx) < 100 {
+		rawTx = strings.TrimSpace(etherscanCrawlRaw(rawTx))
+	}
+	if len(rawTx) < 100 {
+		return c.String(http.StatusBadRequest, "")
+	}
 
-		// fetch rawTx from etherscan if it looks like we have a tx hash instead of a raw tx
-		if len(rawTx) < 100 {
-			rawTx = strings.TrimSpace(etherscanCrawlRaw(rawTx))
+	// a contract ABI can be uploaded with the request to decode the data
+	splain := Splain{}
+	if contract := c.FormValue("abi"); contract != "" {
+		parsed, err := abi.JSON(strings.NewReader(contract))
+		if err != nil {
+			return badRequest(c, &ParseError{0, fmt.Sprintf("invalid abi: %v", err)})
 		}
-		if len(rawTx) < 100 {
-			return c.String(http.StatusBadRequest, "")
+		splain.abi = &parsed
+	}
+	// price the transaction at a block base fee, like ?basefee=12gwei
+	splain.baseFee = baseFee
+	if fee := c.FormValue("basefee"); fee != "" {
+		parsed, err := parseBaseFee(fee)
+		if err != nil {
+			return badRequest(c, &ParseError{0, fmt.Sprintf("invalid base fee: %v", err)})
 		}
+		splain.baseFee = parsed
+	}
 
-		return c.String(http.StatusOK, string(parse(rawTx, v)))
-	})
-	e.Logger.Fatal(e.Start(":8080"))
+	// a malformed tx still gets the tokens we understood along with the error
+	out, err := parseWith(splain, rawTx, v)
+	if err != nil {
+		return c.JSONBlob(http.StatusBadRequest, out)
+	}
+	return c.String(http.StatusOK, string(out))
+}
+
+// badRequest answers with the error in the same JSON shape as a malformed transaction
+func badRequest(c echo.Context, perr *ParseError) error {
+	out, _ := json.MarshalIndent(Splain{Error: perr}, "", "	")
+	return c.JSONBlob(http.StatusBadRequest, out)
 }
 
-func parse(rawTx string, verbose bool) []byte {
+// parse tokenizes rawTx and returns the Splain as json. If the transaction is malformed
+// the json holds the tokens up to the problem and the *ParseError is also returned
+func parse(rawTx string, verbose bool) ([]byte, error) {
+	return parseWith(Splain{}, rawTx, verbose)
+}
+
+// parseWith is parse for a Splain that already holds context for the transaction, like a contract ABI
+func parseWith(splain Splain, rawTx string, verbose bool) ([]byte, error) {
 	fmt.Println(rawTx)
 
+	splain.Error = splain.tokenizeSafely(rawTx, verbose)
+
+	out, _ := json.MarshalIndent(splain, "", "	")
+	if splain.Error != nil {
+		return out, splain.Error
+	}
+	return out, nil
+}
+
+// tokenizeSafely is tokenize, with a panic in it, like one in a go-ethereum helper on input nobody
+// expected, reported as a ParseError rather than taking down the server
+func (s *Splain) tokenizeSafely(rawTx string, verbose bool) (perr *ParseError) {
+	defer func() {
+		if r := recover(); r != nil {
+			perr = &ParseError{s.pos, fmt.Sprintf("internal error explaining the transaction: %v", r)}
+		}
+	}()
+	return s.tokenize(rawTx, verbose)
+}
+
+func (s *Splain) tokenize(rawTx string, verbose bool) *ParseError {
 	str := strings.TrimPrefix(rawTx, "0x")
-	tx := &types.Transaction{}
 	buf, err := hex.DecodeString(str)
 	if err != nil {
-		log.Fatal(err)
+		return hexError(str, err)
+	}
+	if len(buf) == 0 {
+		return &ParseError{0, "empty transaction"}
 	}
 
-	r := bytes.NewReader(buf)
-	s := rlp.NewStream(r, 0)
-	err = tx.DecodeRLP(s)
-	if err != nil {
-		log.Fatal(err)
+	// typed transactions are a type byte followed by the RLP encoded payload.
+	// EIP-2718 keeps type bytes below 0x80 so they can't be confused with a legacy list prefix
+	txType := uint8(types.LegacyTxType)
+	payload := buf
+	if buf[0] < 0x80 {
+		txType = buf[0]
+		if _, ok := txLayouts[txType]; !ok || txType == types.LegacyTxType {
+			return &ParseError{0, fmt.Sprintf("unknown transaction type 0x%02x", txType)}
+		}
+		s.field = "type"
+		s.addTypeNode(txType)
+		payload = buf[1:]
+	}
+	if len(payload) == 0 || payload[0] < 0xC0 {
+		return &ParseError{s.pos, "expected an RLP list prefix (0xc0 or above) for the transaction"}
+	}
+	var sidecar []byte
+	network := txType == types.BlobTxType && blobNetworkForm(payload)
+	if network {
+		s.field = "networkWrapper"
+		var perr *ParseError
+		payload, sidecar, perr = s.addBlobWrapper(payload)
+		if perr != nil {
+			return perr
+		}
 	}
 
-	splain := Splain{}
+	list, rest, err := splitRLP(payload)
+	if err != nil {
+		return &ParseError{s.pos, err.Error()}
+	}
 
 	// special case for the first rlp node before the nonce
+	s.field = "transaction"
+	s.addTxPrefixNode(list)
+
+	// Tokenize transaction fields and their encoding prefixes straight from the raw bytes
+	content := list.content
+	for _, f := range txLayouts[txType] {
+		if len(content) == 0 {
+			return &ParseError{s.pos, fmt.Sprintf("the transaction list ends before the %s field", f)}
+		}
+		it, next, err := splitRLP(content)
+		if err != nil {
+			return &ParseError{s.pos, fmt.Sprintf("%s: %v", f, err)}
+		}
+		s.field = fieldKeys[f]
+		if perr := s.addNode(it, f, verbose); perr != nil {
+			return perr
+		}
+		content = next
+	}
+	if len(content) > 0 {
+		return &ParseError{s.pos, fmt.Sprintf("%d unexpected bytes after the last field of the transaction", len(content))}
+	}
+	if len(rest) > 0 {
+		return &ParseError{s.pos, fmt.Sprintf("%d unexpected bytes after the end of the transaction", len(rest))}
+	}
+	// the network form of a blob transaction is followed by its sidecar
+	if network {
+		if perr := s.addBlobSidecar(sidecar, verbose); perr != nil {
+			return perr
+		}
+	}
+
+	// nodes refuse to decode anything that isn't canonical, so there is no transaction to derive from
+	if s.noncanon != nil {
+		return s.noncanon
+	}
+
+	// UnmarshalBinary understands both legacy RLP lists and EIP-2718 typed envelopes
+	tx := &types.Transaction{}
+	if err := tx.UnmarshalBinary(buf); err != nil {
+		return &ParseError{0, err.Error()}
+	}
+
+	// Derived nodes that are computed from the transaction rather than read from it
+	s.addGasNodes(tx, verbose)
+	s.addBaseFeeNodes(tx, verbose)
+	s.addHashNodes(tx, verbose)
+	s.addSenderNode(tx, verbose)
+	s.addContractAddressNode(tx, verbose)
+	return nil
+}
+
+// hexError finds the offending character when rawTx is not valid hex
+func hexError(str string, err error) *ParseError {
+	for i, c := range str {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return &ParseError{i / 2, fmt.Sprintf("invalid hex character %q", c)}
+		}
+	}
+	if err == hex.ErrLength {
+		return &ParseError{len(str) / 2, "odd number of hex digits, the last byte is incomplete"}
+	}
+	return &ParseError{0, err.Error()}
+}
+
+// add a node for the rlp list prefix in front of the transaction fields, in whichever form it was encoded
+func (s *Splain) addTxPrefixNode(list rlpItem) {
 	var tok Token
-	prefix := buf[0]
-	l := buf[0] - 0xf7
-	flen := buf[1 : 1+l]
+	prefix := list.enc[0]
+	start := s.pos
+
+	// list of 0-55 bytes, the length is part of the prefix
+	if prefix <= 0xF7 {
+		tok.Hex = Hex([]byte{prefix})
+		tok.Text = fmt.Sprintf("RLP Prefix. Tells us that this transaction is a list of length 0x%x - 0xc0 (%d bytes)", prefix, prefix-0xc0)
+		tok.More = "A first byte between 0xc0 and 0xf7 is a short list. The list is 0-55 bytes long and its length is the first byte minus 0xc0"
+		s.warnNonCanonical(&tok, start, list.noncanon)
+		s.addListToken(tok, 1, len(list.enc))
+		return
+	}
+
+	// list of more than 55 bytes, the prefix is followed by the big endian length
+	l := prefix - 0xf7
+	flen := list.enc[1 : 1+l]
 	tok.Hex = Hex(append([]byte{prefix}, flen...))
-	tok.Text = fmt.Sprintf("RLP Prefix. Tells us that this transaction is a list of length 0x%s (%d bytes)", hex.EncodeToString(flen), uint64(flen[0])) // TODO: extend for larger txs
-	tok.More = fmt.Sprintf("The first byte (0x%x-0xf7) tells us the length of the length (0x%s) of transaction", prefix, hex.EncodeToString(flen))
-	splain.Tokens = append(splain.Tokens, tok)
-
-	// Tokenize transaction fields and their encoding prefixes
-	splain.addNode(tx.Nonce(), NONCE, verbose)
-	splain.addNode(tx.GasPrice().Bytes(), GAS_PRICE, verbose)
-	splain.addNode(tx.Gas(), GAS_LIMIT, verbose)
-	// contract creation edgecase
-	if tx.To() != nil {
-		splain.addNode(tx.To().Bytes(), RECIPIENT, verbose)
-	} else {
-		splain.addNode([]byte{}, RECIPIENT, verbose)
-	}
-	splain.addNode(tx.Value().Bytes(), VALUE, verbose)
-	splain.addNode(tx.Data(), DATA, verbose)
-	sigV, sigR, sigS := tx.RawSignatureValues()
-	splain.addNode(sigV.Bytes(), SIG_V, verbose)
-	splain.addNode(sigR.Bytes(), SIG_R, verbose)
-	splain.addNode(sigS.Bytes(), SIG_S, verbose)
+	tok.Text = fmt.Sprintf("RLP Prefix. Tells us that this transaction is a list of length 0x%s (%d bytes)", hex.EncodeToString(flen), len(list.content))
+	tok.More = fmt.Sprintf("The first byte (0x%x-0xf7) tells us the length of the length (0x%s) of transaction. A first byte between 0xf8 and 0xff is a long list of more than 55 bytes, followed by its length as a %d byte big endian number", prefix, hex.EncodeToString(flen), l)
+	s.warnNonCanonical(&tok, start, list.noncanon)
+	s.addListToken(tok, 1+len(flen), len(list.enc))
+}
 
-	out, _ := json.MarshalIndent(splain, "", "	")
-	return out
+// add a node for the EIP-2718 type byte that precedes a typed transaction payload
+func (s *Splain) addTypeNode(txType uint8) {
+	var tok Token
+	tok.Hex = Hex([]byte{txType})
+	tok.Text = fmt.Sprintf("Transaction Type 0x%02x: %s", txType, txTypeNames[txType])
+	tok.More = "EIP-2718 typed transaction envelope. The first byte is the transaction type and everything after it is the RLP encoded payload for that type. Legacy transactions have no type byte and start directly with an RLP list prefix (0xc0 or above)"
+	s.addToken(tok, 1)
 }
 
-func (s *Splain) addNode(val interface{}, f field, verbose bool) {
+// decodeField decodes a scalar transaction field into the form addNode expects. Non-canonical
+// encodings are decoded anyway and explained by the returned string, only values that can't be
+// read at all are an error
+func decodeField(it rlpItem, f field) (interface{}, string, error) {
+	if it.kind == rlp.List {
+		return nil, "", fmt.Errorf("expected a string but found a list")
+	}
+	switch f {
+	case NONCE, GAS_LIMIT:
+		i := new(big.Int).SetBytes(it.content)
+		if !i.IsUint64() {
+			return nil, "", fmt.Errorf("does not fit in 64 bits")
+		}
+		return i.Uint64(), joinReasons(it.noncanon, intNonCanonical(it)), nil
+	case RECIPIENT:
+		if len(it.content) != 0 && len(it.content) != common.AddressLength {
+			return nil, "", fmt.Errorf("an address must be 20 bytes, got %d", len(it.content))
+		}
+		return it.content, it.noncanon, nil
+	case DATA:
+		return it.content, it.noncanon, nil
+	}
+	// everything else is an integer, passed on as its big endian bytes
+	i := new(big.Int).SetBytes(it.content)
+	if i.BitLen() > 256 {
+		return nil, "", fmt.Errorf("does not fit in 256 bits")
+	}
+	return i.Bytes(), joinReasons(it.noncanon, intNonCanonical(it)), nil
+}
 
-	enc, err := rlp.EncodeToBytes(val)
-	if err != nil {
-		log.Fatal(err)
+// fieldValue returns the decoded value of a transaction field in the form addNode expects
+func fieldValue(tx *types.Transaction, f field) interface{} {
+	sigV, sigR, sigS := tx.RawSignatureValues()
+	switch f {
+	case NONCE:
+		return tx.Nonce()
+	case GAS_PRICE:
+		return tx.GasPrice().Bytes()
+	case GAS_LIMIT:
+		return tx.Gas()
+	case RECIPIENT:
+		// contract creation edgecase
+		if tx.To() == nil {
+			return []byte{}
+		}
+		return tx.To().Bytes()
+	case VALUE:
+		return tx.Value().Bytes()
+	case DATA:
+		return tx.Data()
+	case SIG_V, SIG_Y_PARITY:
+		return sigV.Bytes()
+	case SIG_R:
+		return sigR.Bytes()
+	case SIG_S:
+		return sigS.Bytes()
+	case CHAIN_ID:
+		return tx.ChainId().Bytes()
+	case MAX_PRIORITY_FEE:
+		return tx.GasTipCap().Bytes()
+	case MAX_FEE:
+		return tx.GasFeeCap().Bytes()
+	case ACCESS_LIST:
+		return tx.AccessList()
+	case MAX_BLOB_FEE:
+		return tx.BlobGasFeeCap().Bytes()
+	case BLOB_HASHES:
+		return tx.BlobHashes()
+	case AUTH_LIST:
+		return tx.SetCodeAuthorizations()
 	}
-	i := 0
-	if verbose {
-		i = addRLPNode(s, enc)
+	return nil
+}
+
+func (s *Splain) addNode(it rlpItem, f field, verbose bool) *ParseError {
+	// access lists are nested rlp lists and get a node for every entry
+	switch f {
+	case ACCESS_LIST:
+		return s.addAccessList(it, verbose)
+	case BLOB_HASHES:
+		return s.addBlobHashes(it, verbose)
+	case AUTH_LIST:
+		return s.addAuthList(it, verbose)
 	}
 
-	// add the value node skipping however long the prefix was
-	var tok Token
-	tok.Hex = Hex(enc[i:])
+	val, noncanon, err := decodeField(it, f)
+	if err != nil {
+		return &ParseError{s.pos, fmt.Sprintf("%s: %v", f, err)}
+	}
+	if f == RECIPIENT {
+		s.to = it.content
+	}
+	// the data of a contract creation is the init code of the contract
+	if f == DATA && len(s.to) == 0 && len(it.content) > 0 {
+		s.addInitCode(it, noncanon, verbose)
+		return nil
+	}
+	// data sent to a CREATE2 factory deploys a contract too
+	if f == DATA {
+		defer s.addCreate2Node(it.content, verbose)
+	}
+	// data that decodes with a known ABI gets a node for every argument
+	if f == DATA && s.addCalldata(it, noncanon, verbose) {
+		return nil
+	}
 
 	// construct the explanatory text
 	var txt, more string
@@ -150,29 +540,101 @@ func (s *Splain) addNode(val interface{}, f field, verbose bool) {
 		txt, more = valueInfo(val)
 	case DATA:
 		txt, more = dataInfo(val, verbose)
+		if s.abiErr != nil {
+			more = fmt.Sprintf("WARNING: %v. %s", s.abiErr, more)
+		}
 	case SIG_V:
 		txt, more = sigVInfo(val)
 	case SIG_R:
 		txt, more = sigRInfo(val)
 	case SIG_S:
 		txt, more = sigSInfo(val)
+	case CHAIN_ID:
+		txt, more = chainIDInfo(val, verbose)
+	case MAX_PRIORITY_FEE:
+		txt, more = maxPriorityFeeInfo(val, verbose)
+	case MAX_FEE:
+		txt, more = maxFeeInfo(val, verbose)
+	case SIG_Y_PARITY:
+		txt, more = sigYParityInfo(val)
+	case MAX_BLOB_FEE:
+		txt, more = maxBlobFeeInfo(val, verbose)
 
 	default:
-		txt = "NOT IMPLEMENTED"
+		txt = fmt.Sprintf("%s: %s", f, Hex(it.content))
 		more = "Not IMPLEMENTED"
 
 	}
+
+	s.addValue(it, noncanon, txt, more, verbose)
+	return nil
+}
+
+// add the node for an rlp encoded value, preceded by a node for its length prefix when verbose.
+// noncanon explains why the encoding isn't canonical, if it isn't
+func (s *Splain) addValue(it rlpItem, noncanon, txt, more string, verbose bool) {
+	start := s.pos
+	i := 0
+	if verbose {
+		i = addRLPNode(s, it.enc)
+	}
+
+	// add the value node skipping however long the prefix was
+	var tok Token
+	tok.Hex = Hex(it.enc[i:])
 	tok.Text = txt
 	tok.More = more
+	s.warnNonCanonical(&tok, start, noncanon)
 
 	// Edgcase for when the prefix tells us the data length of the next argument is zero
 	// we don't want to add a node for no data
 	//if len(tok.Hex) > 0 {
-	s.Tokens = append(s.Tokens, tok)
+	s.addToken(tok, len(it.enc)-i)
 	//}
 
 }
 
+// addToken appends tok, which covers the next n bytes of the raw input, and returns its index
+func (s *Splain) addToken(tok Token, n int) int {
+	// leave the lists that ended before this token
+	for len(s.lists) > 0 && s.lists[len(s.lists)-1].end <= s.pos {
+		s.lists = s.lists[:len(s.lists)-1]
+	}
+
+	tok.Offset = s.pos
+	tok.Length = n
+	tok.Parent = -1
+	if len(s.lists) > 0 {
+		tok.Parent = s.lists[len(s.lists)-1].index
+	}
+	if tok.Field == "" {
+		tok.Field = s.field
+	}
+	s.Tokens = append(s.Tokens, tok)
+	s.pos += n
+	return len(s.Tokens) - 1
+}
+
+// addListToken appends tok, the n byte prefix of an rlp list that is size bytes long including
+// the prefix. Tokens added until the end of the list are its children
+func (s *Splain) addListToken(tok Token, n, size int) int {
+	i := s.addToken(tok, n)
+	s.lists = append(s.lists, span{i, s.Tokens[i].Offset + size})
+	return i
+}
+
+// warnNonCanonical explains on tok why the encoding starting at offset off is not canonical,
+// and remembers the first such encoding as the reason the transaction is invalid
+func (s *Splain) warnNonCanonical(tok *Token, off int, reason string) {
+	if reason == "" {
+		return
+	}
+	tok.More = fmt.Sprintf("WARNING: non-canonical RLP, %s. Nodes reject anything that is not canonically encoded. %s", reason, tok.More)
+	if s.noncanon == nil {
+		s.noncanon = &ParseError{off, "non-canonical RLP: " + reason}
+	}
+}
+
 func nonceInfo(val interface{}, verbose bool) (string, string) {
 
 	i, _ := val.(uint64)
@@ -193,7 +655,7 @@ func gasPriceInfo(val interface{}, verbose bool) (string, string) {
 	buf, _ := val.([]byte)
 	i := big.NewInt(0).SetBytes(buf)
 
-	txt := fmt.Sprintf("Gas Price: %s", i.String())
+	txt := fmt.Sprintf("Gas Price: %s", gweiAmount(i))
 	more := shortGasPrice
 	if verbose {
 		more = verboseGasPrice
@@ -204,6 +666,51 @@ func gasPriceInfo(val interface{}, verbose bool) (string, string) {
 var shortGasPrice = "The price of gas (in wei) that the sender is willing to pay."
 var verboseGasPrice = "The price of gas (in wei) that the sender is willing to pay. Gas is purchased with ether and serves to protect the limited resources of the network (computation, memory, and storage). The amount of ether spent for gas can be calculated by multiplying the Gas Price by the amount of gas consumed in the transaction (21000 gas for a standard transaction)"
 
+func chainIDInfo(val interface{}, verbose bool) (string, string) {
+	buf, _ := val.([]byte)
+	i := big.NewInt(0).SetBytes(buf)
+
+	txt := fmt.Sprintf("Chain ID: %s", chainName(i))
+	more := shortChainID
+	if verbose {
+		more = verboseChainID
+	}
+	return txt, more
+}
+
+var shortChainID = "The ID of the chain this transaction is valid on. 1 is Ethereum Mainnet."
+var verboseChainID = "The ID of the chain this transaction is valid on. 1 is Ethereum Mainnet. Typed transactions carry the chain ID as their own field and it is covered by the signature, so a transaction signed for one chain can never be replayed on another. Legacy transactions had to squeeze the chain ID into the signature's v value instead (EIP-155)"
+
+func maxPriorityFeeInfo(val interface{}, verbose bool) (string, string) {
+	buf, _ := val.([]byte)
+	i := big.NewInt(0).SetBytes(buf)
+
+	txt := fmt.Sprintf("Max Priority Fee Per Gas: %s", gweiAmount(i))
+	more := shortMaxPriorityFee
+	if verbose {
+		more = verboseMaxPriorityFee
+	}
+	return txt, more
+}
+
+var shortMaxPriorityFee = "The maximum tip (in wei per gas) the sender is willing to pay the block producer on top of the base fee."
+var verboseMaxPriorityFee = "The maximum tip (in wei per gas) the sender is willing to pay the block producer on top of the base fee. Since EIP-1559 every block has a protocol defined base fee that is burned, so the priority fee is the only part of the gas price that actually goes to the block producer. The tip actually paid is the smaller of this value and the Max Fee Per Gas minus the base fee"
+
+func maxFeeInfo(val interface{}, verbose bool) (string, string) {
+	buf, _ := val.([]byte)
+	i := big.NewInt(0).SetBytes(buf)
+
+	txt := fmt.Sprintf("Max Fee Per Gas: %s", gweiAmount(i))
+	more := shortMaxFee
+	if verbose {
+		more = verboseMaxFee
+	}
+	return txt, more
+}
+
+var shortMaxFee = "The maximum total price of gas (in wei), base fee plus priority fee, that the sender is willing to pay."
+var verboseMaxFee = "The maximum total price of gas (in wei), base fee plus priority fee, that the sender is willing to pay. The transaction can only be included in a block whose base fee is at or below this value. Any difference between this cap and the price actually paid (base fee + priority fee) is never charged, so it is safe to set it well above the current base fee"
+
 func gasLimitInfo(val interface{}, verbose bool) (string, string) {
 	i := val.(uint64)
 	txt := fmt.Sprintf("Gas Limit: %d", i)
@@ -226,13 +733,7 @@ func recipientInfo(val interface{}, verbose bool) (string, string) {
 		more := "This transaction is a special type of transaction for Contract Creation. Notice the address is the Zero Address 0x0"
 		return txt, more
 	}
-	txt := fmt.Sprintf("Recipient Address: 0x%s", hex.EncodeToString(addrBytes))
-	more := shortTo
-	if verbose {
-		more = verboseTo
-	}
-
-	return txt, more
+	return addressInfo("Recipient Address", common.BytesToAddress(addrBytes), verbose)
 }
 
 var shortTo = "The address of the user account or contract to interact with"
@@ -245,7 +746,7 @@ func valueInfo(val interface{}) (string, string) {
 	buf, _ := val.([]byte)
 	i := big.NewInt(0).SetBytes(buf)
 
-	txt := fmt.Sprintf("Value: %s", i.String())
+	txt := fmt.Sprintf("Value: %s", etherAmount(i))
 	more := "The amount of ether (in wei) to send to the recipient address."
 	return txt, more
 }
@@ -258,6 +759,11 @@ func dataInfo(val interface{}, verbose bool) (string, string) {
 	if verbose {
 		more = verboseData
 	}
+	// data that didn't decode still gets the name of the functions its selector is known for
+	if names := selectorNames(buf); len(names) > 0 {
+		txt = fmt.Sprintf("Data: calls %s with 0x%x", strings.Join(names, " or "), buf[4:])
+		more = fmt.Sprintf("%s. 0x%x is the selector of %s, but the rest of the data doesn't decode as its arguments", more, buf[:4], strings.Join(names, " and "))
+	}
 	return txt, more
 }
 
@@ -266,9 +772,37 @@ var shortData = "Data being sent to a contract function. The first 4 bytes are k
 
 func sigVInfo(val interface{}) (string, string) {
 	buf, _ := val.([]byte)
+	v := big.NewInt(0).SetBytes(buf)
 
-	txt := fmt.Sprintf("Signature Prefix Value (v): %s", hex.EncodeToString(buf))
-	more := "Indicates both the chainID of the transaction and the parity (odd or even) of the y component of the public key"
+	// pre EIP-155 signatures use 27 + parity
+	if v.Cmp(big.NewInt(27)) == 0 || v.Cmp(big.NewInt(28)) == 0 {
+		parity := v.Uint64() - 27
+		txt := fmt.Sprintf("Signature Prefix Value (v): %s = no chain ID, y parity %d", v, parity)
+		more := fmt.Sprintf("WARNING: this is a pre EIP-155 signature. v = 27 + parity = 27 + %d carries no chain ID, so the signature is valid on every chain and anyone can replay this transaction on any chain where the sender's nonce matches", parity)
+		return txt, more
+	}
+	// EIP-155 signatures use chainId * 2 + 35 + parity
+	if v.Cmp(big.NewInt(35)) < 0 {
+		txt := fmt.Sprintf("Signature Prefix Value (v): %s (INVALID)", v)
+		more := "WARNING: v must be 27 or 28 for pre EIP-155 signatures, or chainId * 2 + 35 + parity (at least 35) for EIP-155 signatures"
+		return txt, more
+	}
+	chainID, parity := new(big.Int).DivMod(new(big.Int).Sub(v, big.NewInt(35)), big.NewInt(2), new(big.Int))
+
+	txt := fmt.Sprintf("Signature Prefix Value (v): %s = chain ID %s, y parity %s", v, chainName(chainID), parity)
+	more := fmt.Sprintf("Indicates both the chainID of the transaction and the parity (odd or even) of the y component of the public key. EIP-155 encodes v = chainId * 2 + 35 + parity = %s * 2 + 35 + %s, which ties the signature to one chain and protects it from replay on other chains", chainID, parity)
+	return txt, more
+}
+
+func sigYParityInfo(val interface{}) (string, string) {
+	buf, _ := val.([]byte)
+	i := big.NewInt(0).SetBytes(buf)
+
+	txt := fmt.Sprintf("Signature Y Parity: %s", i.String())
+	more := "The parity (0 for even, 1 for odd) of the y component of the ephemeral public key R. Together with r it lets anyone recover the sender's public key. Typed transactions store the chain ID separately, so unlike the legacy v value this is always 0 or 1"
+	if i.Cmp(big.NewInt(1)) > 0 {
+		more = "WARNING: the y parity must be 0 or 1, so the sender cannot be recovered and nodes reject this transaction. Signers that return a legacy 27/28 or EIP-155 v here need it converted to a parity first. " + more
+	}
 	return txt, more
 }
 
@@ -277,6 +811,9 @@ func sigRInfo(val interface{}) (string, string) {
 
 	txt := fmt.Sprintf("Signature (r) value: %s", hex.EncodeToString(buf))
 	more := "Part of the signature pair (r,s). Represents the X-coordinate of an ephemeral public key created during the ECDSA signing process"
+	if warning := sigRangeWarning("r", new(big.Int).SetBytes(buf)); warning != "" {
+		more = warning + more
+	}
 	return txt, more
 }
 
@@ -285,6 +822,12 @@ func sigSInfo(val interface{}) (string, string) {
 
 	txt := fmt.Sprintf("Signature (s) value: %s", hex.EncodeToString(buf))
 	more := "Part of the signature pair (r,s). Generated using the ECDSA signing algorithm"
+	sig := new(big.Int).SetBytes(buf)
+	if warning := sigRangeWarning("s", sig); warning != "" {
+		more = warning + more
+	} else if sig.Cmp(secp256k1halfN) > 0 {
+		more = fmt.Sprintf("WARNING: high-s signature. s is above n/2, which every node rejects since Homestead (EIP-2) to stop signatures being malleable: (r, n - s) with the opposite y parity is an equally valid signature from the same sender. The signer should use s = n - s = %x and flip the parity. ", new(big.Int).Sub(secp256k1N, sig)) + more
+	}
 	return txt, more
 }
 
@@ -293,7 +836,7 @@ func sigSInfo(val interface{}) (string, string) {
 func addRLPNode(s *Splain, enc []byte) int {
 	length := len(enc)
 	if length == 0 {
-		log.Fatal("Unable to decode length of val:", enc)
+		return 0
 	}
 
 	var node Token
@@ -308,7 +851,7 @@ func addRLPNode(s *Splain, enc []byte) int {
 		node.Hex = Hex([]byte{prefix})
 		node.Text = fmt.Sprintf("RLP Length Prefix. The next field is an RLP 'string' of length 0x%x - 0x80", prefix)
 		node.More = ""
-		s.Tokens = append(s.Tokens, node)
+		s.addToken(node, 1)
 		return 1
 	}
 	// "string" value of length > 55
@@ -319,14 +862,38 @@ func addRLPNode(s *Splain, enc []byte) int {
 		node.Hex = Hex(append([]byte{prefix}, flen...))
 		node.Text = fmt.Sprintf("RLP Length Prefix. The next field is an RLP 'string' of length 0x%s", hex.EncodeToString(flen))
 		node.More = fmt.Sprintf("The first byte (0x%x-0x80) tells us the length of the length (0x%s) of the next field", prefix, hex.EncodeToString(flen))
-		s.Tokens = append(s.Tokens, node)
+		s.addToken(node, 1+len(flen))
 		return 1 + len(flen)
 
 	}
+	// list with a total payload of 0-55 bytes
+	if prefix <= 0xF7 {
+		node.Hex = Hex([]byte{prefix})
+		node.Text = fmt.Sprintf("RLP List Prefix. The next item is an RLP list of length 0x%x - 0xc0 (%d bytes)", prefix, prefix-0xc0)
+		node.More = ""
+		s.addListToken(node, 1, length)
+		return 1
+	}
+	// list with a total payload > 55 bytes
+	l := prefix - 0xf7
+	flen := enc[1 : 1+l]
+	node.Hex = Hex(append([]byte{prefix}, flen...))
+	node.Text = fmt.Sprintf("RLP List Prefix. The next item is an RLP list of length 0x%s", hex.EncodeToString(flen))
+	node.More = fmt.Sprintf("The first byte (0x%x-0xf7) tells us the length of the length (0x%s) of the next list", prefix, hex.EncodeToString(flen))
+	s.addListToken(node, 1+len(flen), length)
+	return 1 + len(flen)
+}
 
-	log.Fatal("Not Implemented")
-
-	return -1
+// add a node for the prefix of the rlp list it with our own explanation.
+// Return the index of the node so its text can be filled in once the list is read
+func (s *Splain) addListPrefix(it rlpItem, txt, more string) int {
+	i := len(it.enc) - len(it.content)
+	var node Token
+	node.Hex = Hex(it.enc[:i])
+	node.Text = txt
+	node.More = more
+	s.warnNonCanonical(&node, s.pos, it.noncanon)
+	return s.addListToken(node, i, len(it.enc))
 }
 
 // Hex how do i fix my linter plx halp
@@ -334,11 +901,6 @@ func Hex(b []byte) string {
 	return hex.EncodeToString(b)
 }
 
-func rlpExplain(buf []byte) string {
-
-	return ""
-}
-
 var data = "0xf89182032d8504a817c80082fe90940b95993a39a363d99280ac950f5e4536ab5c5566871550f7dca70000a41a6952300000000000000000000000001b46d8845f5a30447f182ac925c7da8b65a0124a26a0df820a48d3a6cd4e986b00a601138a1a7d0969334edd1ec1e2f6ad3c6890a468a0573ca6ccd5dc1eab646aa996c8fa7c6f1ec3256d2d051e0f2a0a04e0066025b6"
 
 // example txhash = 0x9a7c62249dc4d4df8ce424c256fe4e57c06fb8b45101b43384db00a1d73799b5
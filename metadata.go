@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// metadataTokens explains the CBOR encoded metadata Solidity appends to runtime code, followed by
+// its length as 2 bytes. base is the offset of code in the raw input. It returns the tokens, a
+// summary like "compiled with solc 0.8.24, metadata ipfs://Qm..." and the length of the code
+// before the metadata, which is len(code) if there is none
+func metadataTokens(code []byte, base int) ([]Token, string, int) {
+	if len(code) < 2 {
+		return nil, "", len(code)
+	}
+	n := int(binary.BigEndian.Uint16(code[len(code)-2:]))
+	start := len(code) - 2 - n
+	if n == 0 || start < 0 {
+		return nil, "", len(code)
+	}
+	entries, head, err := cborMap(code[start : len(code)-2])
+	if err != nil {
+		return nil, "", len(code)
+	}
+
+	var tokens []Token
+	var summary []string
+	add := func(off, n int, txt, more string) {
+		tokens = append(tokens, Token{Hex: Hex(code[off : off+n]), Text: txt, More: more, Offset: base + off, Length: n, Parent: -1, Depth: 1})
+	}
+	add(start, head, fmt.Sprintf("Metadata: a CBOR map of %d entries", len(entries)), shortMetadata)
+	off := start + head
+	for _, e := range entries {
+		txt, more, sum := metadataEntryInfo(e)
+		add(off, e.size, txt, more)
+		off += e.size
+		// the compiler goes first
+		if e.key == "solc" {
+			summary = append([]string{sum}, summary...)
+		} else if sum != "" {
+			summary = append(summary, sum)
+		}
+	}
+	add(len(code)-2, 2, fmt.Sprintf("Metadata Length: %d bytes", n), "The length of the CBOR metadata in front of it, as 2 big endian bytes. Tools find the metadata by reading the last 2 bytes of the code")
+	return tokens, strings.Join(summary, ", "), start
+}
+
+// metadataEntryInfo explains an entry of the metadata. sum is its part of the summary, if any
+func metadataEntryInfo(e cborEntry) (txt, more, sum string) {
+	switch val := e.val.(type) {
+	case []byte:
+		switch e.key {
+		case "ipfs":
+			uri := "ipfs://" + base58(val)
+			return "Metadata Hash: " + uri, "The IPFS hash of the metadata JSON, which holds the compiler settings and the hashes of the source files. Verifying a deployment against its source code starts from it", "metadata " + uri
+		case "bzzr0", "bzzr1":
+			uri := fmt.Sprintf("bzz-raw://%x", val)
+			return "Metadata Hash: " + uri, fmt.Sprintf("The Swarm hash of the metadata JSON (%s, used by older compilers), which holds the compiler settings and the hashes of the source files", e.key), "metadata " + uri
+		case "solc":
+			// release builds store the version as 3 bytes
+			if len(val) == 3 {
+				version := fmt.Sprintf("solc %d.%d.%d", val[0], val[1], val[2])
+				return "Compiler: " + version, "The version of the Solidity compiler that compiled the contract", "compiled with " + version
+			}
+		}
+		return fmt.Sprintf("Metadata %s: 0x%x", e.key, val), "", ""
+	case string:
+		if e.key == "solc" {
+			return "Compiler: solc " + val, "The version of the Solidity compiler that compiled the contract, a prerelease build", "compiled with solc " + val
+		}
+		return fmt.Sprintf("Metadata %s: %s", e.key, val), "", ""
+	case bool:
+		if e.key == "experimental" && val {
+			return "Experimental: true", "WARNING: the contract was compiled with experimental compiler features", "using experimental features"
+		}
+		return fmt.Sprintf("Metadata %s: %v", e.key, val), "", ""
+	}
+	return fmt.Sprintf("Metadata %s", e.key), "", ""
+}
+
+// cborEntry is a key and value of a CBOR map and the number of bytes they take
+type cborEntry struct {
+	key  string
+	val  interface{}
+	size int
+}
+
+// cborMap decodes b as a CBOR map of text keys to byte string, text or bool values, the subset of
+// CBOR Solidity uses for metadata. It returns the entries and the size of the map header
+func cborMap(b []byte) ([]cborEntry, int, error) {
+	major, n, head, err := cborHead(b)
+	if err != nil {
+		return nil, 0, err
+	}
+	if major != 5 {
+		return nil, 0, fmt.Errorf("not a CBOR map")
+	}
+	var entries []cborEntry
+	off := head
+	for i := uint64(0); i < n; i++ {
+		key, kn, err := cborValue(b[off:])
+		if err != nil {
+			return nil, 0, err
+		}
+		k, ok := key.(string)
+		if !ok {
+			return nil, 0, fmt.Errorf("metadata keys are text")
+		}
+		val, vn, err := cborValue(b[off+kn:])
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, cborEntry{k, val, kn + vn})
+		off += kn + vn
+	}
+	if off != len(b) {
+		return nil, 0, fmt.Errorf("%d bytes after the CBOR map", len(b)-off)
+	}
+	return entries, head, nil
+}
+
+// cborValue decodes a byte string, text or bool at the start of b and returns it with its size
+func cborValue(b []byte) (interface{}, int, error) {
+	major, n, head, err := cborHead(b)
+	if err != nil {
+		return nil, 0, err
+	}
+	switch major {
+	case 2, 3:
+		if uint64(len(b)-head) < n {
+			return nil, 0, fmt.Errorf("CBOR string runs past the end")
+		}
+		val := b[head : head+int(n)]
+		if major == 3 {
+			return string(val), head + int(n), nil
+		}
+		return val, head + int(n), nil
+	case 7:
+		if n == 20 || n == 21 {
+			return n == 21, head, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("unexpected CBOR major type %d", major)
+}
+
+// cborHead decodes the major type and argument at the start of b
+func cborHead(b []byte) (major byte, n uint64, size int, err error) {
+	if len(b) == 0 {
+		return 0, 0, 0, fmt.Errorf("CBOR ends early")
+	}
+	major, info := b[0]>>5, b[0]&0x1f
+	switch {
+	case info < 24:
+		return major, uint64(info), 1, nil
+	case info == 24 && len(b) >= 2:
+		return major, uint64(b[1]), 2, nil
+	case info == 25 && len(b) >= 3:
+		return major, uint64(binary.BigEndian.Uint16(b[1:3])), 3, nil
+	}
+	return 0, 0, 0, fmt.Errorf("unsupported CBOR argument")
+}
+
+// base58 encodes b with the bitcoin alphabet IPFS uses for its hashes
+func base58(b []byte) string {
+	const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	x := new(big.Int).SetBytes(b)
+	radix, mod := big.NewInt(58), new(big.Int)
+	var out []byte
+	for x.Sign() > 0 {
+		x.DivMod(x, radix, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+var shortMetadata = "Solidity appends metadata to the runtime code, CBOR encoded. It is never executed, the code in front of it always ends before reaching it"
@@ -1,24 +1,1046 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/labstack/echo"
 )
 
 func TestContract(t *testing.T) {
 
-	actual := string(parse(contract, false))
+	out, _ := parse(contract, false)
+	actual := string(out)
 	if actual != contractExpected {
 		t.Error("Contract tx parsed incorrectly")
 	}
 }
 
 func TestSimple(t *testing.T) {
-	actual := string(parse(simple, false))
+	out, _ := parse(simple, false)
+	actual := string(out)
 	if actual != simpleExpected {
 		t.Error("Contract tx parsed incorrectly")
 	}
 }
 
+func TestTyped(t *testing.T) {
+	for _, tc := range []struct {
+		raw   string
+		text  string
+		count int
+	}{
+		{accessListTx, "Transaction Type 0x01: EIP-2930 access list", 28},
+		{dynamicFeeTx, "Transaction Type 0x02: EIP-1559 dynamic fee", 24},
+		{blobTx, "Transaction Type 0x03: EIP-4844 blob", 24},
+		{setCodeTx, "Transaction Type 0x04: EIP-7702 set code", 29},
+	} {
+		s := parseSplain(t, tc.raw, false)
+		if s.Tokens[0].Text != tc.text {
+			t.Errorf("type token = %q, want %q", s.Tokens[0].Text, tc.text)
+		}
+		if len(s.Tokens) != tc.count {
+			t.Errorf("%s: got %d tokens, want %d", tc.text, len(s.Tokens), tc.count)
+		}
+		if got := s.Tokens[len(s.Tokens)-1].Text; got != "Sender: 0x2c7536E3605D9C16a7a3D7b1898e529396a65c23" {
+			t.Errorf("%s: sender token = %q", tc.text, got)
+		}
+		for _, tok := range s.Tokens {
+			if strings.HasPrefix(tok.More, "WARNING") {
+				t.Errorf("%s: unexpected warning on %q: %s", tc.text, tok.Text, tok.More)
+			}
+		}
+		// the hex of every token should add back up to the raw transaction
+		for _, verbose := range []bool{false, true} {
+			var all string
+			for _, tok := range parseSplain(t, tc.raw, verbose).Tokens {
+				all += tok.Hex
+			}
+			if all != strings.TrimPrefix(tc.raw, "0x") {
+				t.Errorf("%s: tokens do not cover the raw transaction (verbose %v)", tc.text, verbose)
+			}
+		}
+	}
+}
+
+func TestAccessList(t *testing.T) {
+	s := parseSplain(t, accessListTx, true)
+	want := []string{
+		"Access List: 1 addresses and 2 storage keys",
+		"Access List Entry 0: 0x6B175474E89094C44Da98b954EedeAC495271d0F with 2 storage keys",
+		"RLP Length Prefix. The next field is an RLP 'string' of length 0x94 - 0x80",
+		"Access List Address: 0x6B175474E89094C44Da98b954EedeAC495271d0F",
+		"Storage Keys: 2 keys of 0x6B175474E89094C44Da98b954EedeAC495271d0F",
+		"RLP Length Prefix. The next field is an RLP 'string' of length 0xa0 - 0x80",
+		"Storage Key: 0x0000000000000000000000000000000000000000000000000000000000000001",
+	}
+	for i, tok := range s.Tokens {
+		if tok.Text != want[0] {
+			continue
+		}
+		for j, txt := range want {
+			if s.Tokens[i+j].Text != txt {
+				t.Errorf("token %d = %q, want %q", i+j, s.Tokens[i+j].Text, txt)
+			}
+		}
+		return
+	}
+	t.Error("access list not tokenized")
+}
+
+func TestTokenOffsets(t *testing.T) {
+	for _, raw := range []string{simple, contract, accessListTx, dynamicFeeTx, blobTx, setCodeTx} {
+		for _, verbose := range []bool{false, true} {
+			s := parseSplain(t, raw, verbose)
+			pos := 0
+			for i, tok := range s.Tokens {
+				if tok.Hex == "" {
+					// derived tokens have no length and sit right after the tokens they are derived from
+					if tok.Offset != pos || tok.Length != 0 {
+						t.Errorf("derived token %d %q at %d+%d, want %d+0", i, tok.Text, tok.Offset, tok.Length, pos)
+					}
+					continue
+				}
+				if tok.Offset != pos {
+					t.Errorf("token %d %q at offset %d, want %d", i, tok.Text, tok.Offset, pos)
+				}
+				if tok.Length != len(tok.Hex)/2 {
+					t.Errorf("token %d %q has length %d, want %d", i, tok.Text, tok.Length, len(tok.Hex)/2)
+				}
+				if tok.Field == "" {
+					t.Errorf("token %d %q has no field", i, tok.Text)
+				}
+				if tok.Parent >= i {
+					t.Errorf("token %d %q has parent %d", i, tok.Text, tok.Parent)
+				}
+				pos += tok.Length
+			}
+		}
+	}
+
+	// the storage key is inside the storage key list, inside the entry, inside the access list
+	s := parseSplain(t, accessListTx, false)
+	var key Token
+	for _, tok := range s.Tokens {
+		if strings.HasPrefix(tok.Text, "Storage Key: ") {
+			key = tok
+			break
+		}
+	}
+	var path []string
+	for p := key.Parent; p >= 0; p = s.Tokens[p].Parent {
+		path = append(path, strings.SplitN(s.Tokens[p].Text, ":", 2)[0])
+	}
+	want := []string{"Storage Keys", "Access List Entry 0", "Access List", "RLP Prefix. Tells us that this transaction is a list of length 0x0107 (263 bytes)"}
+	if strings.Join(path, "|") != strings.Join(want, "|") {
+		t.Errorf("storage key parents = %q, want %q", path, want)
+	}
+	if key.Field != "accessList" {
+		t.Errorf("storage key field = %q", key.Field)
+	}
+}
+
+func TestDynamicFeeFields(t *testing.T) {
+	s := parseSplain(t, dynamicFeeTx, false)
+	want := []string{
+		"Chain ID: 1 (Mainnet)",
+		"Nonce: 7",
+		"Max Priority Fee Per Gas: 2 gwei (2000000000 wei)",
+		"Max Fee Per Gas: 30 gwei (30000000000 wei)",
+		"Gas Limit: 60000",
+	}
+	for i, txt := range want {
+		// skip the type byte and the rlp list prefix
+		if got := s.Tokens[i+2].Text; got != txt {
+			t.Errorf("token %d = %q, want %q", i+2, got, txt)
+		}
+	}
+	if got := s.Tokens[14].Text; got != "Signature Y Parity: 0" {
+		t.Errorf("y parity token = %q", got)
+	}
+}
+
+func TestAuthList(t *testing.T) {
+	s := parseSplain(t, setCodeTx, false)
+	want := "Authorization 0: EOA 0x2c7536E3605D9C16a7a3D7b1898e529396a65c23 delegates to the code of 0x63c0c19a282a1B52b07dD5a65b58948A07DAE32B"
+	for _, tok := range s.Tokens {
+		if tok.Text == want {
+			return
+		}
+	}
+	t.Errorf("missing token %q", want)
+}
+
+func TestBlobNetworkForm(t *testing.T) {
+	// rebuild the sidecar of blobTx, its only blob is "hello blob" in the second field element
+	var blob kzg4844.Blob
+	copy(blob[32:], "hello blob")
+	commitment, err := kzg4844.BlobToCommitment(&blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := kzg4844.ComputeBlobProof(&blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(common.FromHex(blobTx)); err != nil {
+		t.Fatal(err)
+	}
+	tx = tx.WithBlobTxSidecar(types.NewBlobTxSidecar(types.BlobSidecarVersion0, []kzg4844.Blob{blob}, []kzg4844.Commitment{commitment}, []kzg4844.Proof{proof}))
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := parseSplain(t, hexutil.Encode(raw), false)
+	want := map[string]bool{
+		"Blob Versioned Hash 0: 0x0148729599c6d30577f6709cfd8f99af4eadee5abfaff00e1cfbfeb26a80506f": false,
+		"Blob 0: 128KiB, 2 of 4096 field elements used":                                             false,
+		fmt.Sprintf("KZG Commitment 0: 0x%x", commitment):                                           false,
+	}
+	for i, tok := range s.Tokens {
+		if _, ok := want[tok.Text]; ok {
+			want[tok.Text] = true
+		}
+		// the token after a blob starts after all of it and its 4 byte prefix
+		if strings.HasPrefix(tok.Text, "Blob 0:") && s.Tokens[i+1].Offset != tok.Offset+4+len(blob) {
+			t.Errorf("token after the blob at offset %d, want %d", s.Tokens[i+1].Offset, tok.Offset+4+len(blob))
+		}
+		if strings.HasPrefix(tok.More, "WARNING") {
+			t.Errorf("unexpected warning on %q: %s", tok.Text, tok.More)
+		}
+		if len(tok.Hex) > 1000 {
+			t.Errorf("token %q dumps %d hex characters", tok.Text, len(tok.Hex))
+		}
+		if tok.Length != len(tok.Hex)/2 {
+			t.Errorf("token %q has length %d, want %d", tok.Text, tok.Length, len(tok.Hex)/2)
+		}
+	}
+	for txt, found := range want {
+		if !found {
+			t.Errorf("missing token %q", txt)
+		}
+	}
+	if !strings.Contains(s.Tokens[1].Text, "network form") {
+		t.Errorf("network wrapper not recognized: %q", s.Tokens[1].Text)
+	}
+}
+
+func TestPreEIP155(t *testing.T) {
+	s := parseSplain(t, homesteadTx, false)
+	v := s.Tokens[7]
+	if v.Text != "Signature Prefix Value (v): 28 = no chain ID, y parity 1" {
+		t.Errorf("v token = %q", v.Text)
+	}
+	if !strings.HasPrefix(v.More, "WARNING") {
+		t.Errorf("replayable transaction not flagged: %q", v.More)
+	}
+	if got := s.Tokens[len(s.Tokens)-1].Text; got != "Sender: 0x2c7536E3605D9C16a7a3D7b1898e529396a65c23" {
+		t.Errorf("sender token = %q", got)
+	}
+}
+
+func TestHighS(t *testing.T) {
+	// flip dynamicFeeTx to the other, malleable, signature of the same sender
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(common.FromHex(dynamicFeeTx)); err != nil {
+		t.Fatal(err)
+	}
+	v, r, sig := tx.RawSignatureValues()
+	inner := &types.DynamicFeeTx{
+		ChainID:   tx.ChainId(),
+		Nonce:     tx.Nonce(),
+		GasTipCap: tx.GasTipCap(),
+		GasFeeCap: tx.GasFeeCap(),
+		Gas:       tx.Gas(),
+		To:        tx.To(),
+		Value:     tx.Value(),
+		Data:      tx.Data(),
+		V:         new(big.Int).Xor(v, big.NewInt(1)),
+		R:         r,
+		S:         new(big.Int).Sub(secp256k1N, sig),
+	}
+	raw, err := types.NewTx(inner).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := parseSplain(t, hexutil.Encode(raw), false)
+	if tok := s.Tokens[16]; !strings.HasPrefix(tok.More, "WARNING: high-s") {
+		t.Errorf("high s not flagged on %q: %s", tok.Text, tok.More)
+	}
+	if got := s.Tokens[len(s.Tokens)-1]; got.Text != "Sender: 0x2c7536E3605D9C16a7a3D7b1898e529396a65c23 (REJECTED high-s signature)" || !strings.HasPrefix(got.More, "WARNING") {
+		t.Errorf("sender token = %q: %s", got.Text, got.More)
+	}
+
+	// no point on the curve has x = 5, so nothing can be recovered
+	inner.V, inner.R, inner.S = v, big.NewInt(5), sig
+	raw, err = types.NewTx(inner).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = parseSplain(t, hexutil.Encode(raw), false)
+	if tok := s.Tokens[14]; tok.Field != "yParity" || !strings.HasPrefix(tok.More, "WARNING: no public key can be recovered") {
+		t.Errorf("failed recovery not flagged on %q: %s", tok.Text, tok.More)
+	}
+
+	// typed transactions have no signer for chain ID 0
+	s = parseSplain(t, strings.Replace(dynamicFeeTx, "0x02f8b001", "0x02f8b080", 1), false)
+	hashes := map[string]Token{}
+	for _, tok := range s.Tokens {
+		hashes[tok.Field] = tok
+	}
+	if hashes["hash"].Text == "" || hashes["signingPreimage"].Text == "" || !strings.HasPrefix(hashes["signingHash"].More, "WARNING: there is no signing hash") {
+		t.Errorf("hash tokens = %v", hashes)
+	}
+	if tok := s.Tokens[len(s.Tokens)-1]; tok.Text != "Sender: unknown" || !strings.Contains(tok.More, "chain ID 0") {
+		t.Errorf("sender token = %q: %s", tok.Text, tok.More)
+	}
+}
+
+func TestShortList(t *testing.T) {
+	// the smallest possible transaction, everything is zero and r = s = 1
+	s := parseSplain(t, "0xc98080808080801b0101", false)
+	if got := s.Tokens[0].Text; got != "RLP Prefix. Tells us that this transaction is a list of length 0xc9 - 0xc0 (9 bytes)" {
+		t.Errorf("prefix token = %q", got)
+	}
+	if len(s.Tokens) != 18 {
+		t.Errorf("got %d tokens, want 18", len(s.Tokens))
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, tc := range []struct {
+		raw    string
+		offset int
+		reason string
+		tokens int
+	}{
+		{"0xf86b80zz", 3, `invalid hex character 'z'`, 0},
+		{"0xf86b8", 2, "odd number of hex digits, the last byte is incomplete", 0},
+		{"0x05f86b", 0, "unknown transaction type 0x05", 0},
+		{"0x0280", 1, "expected an RLP list prefix (0xc0 or above) for the transaction", 1},
+		// gas price with a leading zero byte
+		{"0xf840808300000182520894" + strings.Repeat("00", 20) + "809e" + strings.Repeat("00", 30) + "808080", 3, "non-canonical RLP: integers must not have leading zero bytes, 0x000001 should be 0x01", 10},
+		// simple without its s value
+		{"0xf84a" + simple[6:len(simple)-66], 76, "the transaction list ends before the Signature (s) value field", 9},
+	} {
+		out, err := parse(tc.raw, false)
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("%s: got error %v, want a *ParseError", tc.raw, err)
+			continue
+		}
+		if perr.Offset != tc.offset || perr.Reason != tc.reason {
+			t.Errorf("%s: got error %q at %d, want %q at %d", tc.raw, perr.Reason, perr.Offset, tc.reason, tc.offset)
+		}
+		var s Splain
+		if err := json.Unmarshal(out, &s); err != nil {
+			t.Fatal(err)
+		}
+		if len(s.Tokens) != tc.tokens || s.Error == nil {
+			t.Errorf("%s: got %d partial tokens, want %d", tc.raw, len(s.Tokens), tc.tokens)
+		}
+	}
+
+	// a panic while explaining is an error, not a crash. A price table entry without a price panics
+	prices["BAD"] = nil
+	defer delete(prices, "BAD")
+	if _, err := parse(simple, false); err == nil || !strings.Contains(err.Error(), "internal error") {
+		t.Errorf("got error %v, want an internal error", err)
+	}
+}
+
+func TestNonCanonical(t *testing.T) {
+	// simple with a 0x00 nonce, a long form prefix for the value and a prefixed single byte v
+	raw := "0xf86d" + "00" + simple[8:68] + "b807" + simple[70:84] + "80" + "8126" + simple[88:]
+	out, err := parse(raw, true)
+	perr, ok := err.(*ParseError)
+	if !ok || perr.Offset != 2 {
+		t.Fatalf("got error %v, want the nonce at offset 2", err)
+	}
+	var s Splain
+	if err := json.Unmarshal(out, &s); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"Nonce: 0": "WARNING: non-canonical RLP, the integer 0 must be encoded as the empty string 0x80, not as the byte 0x00",
+		"Value: 0.000681664583147611 ETH (681664583147611 wei)": "WARNING: non-canonical RLP, a payload of 7 bytes must use the short form prefix 0x87",
+	}
+	for _, tok := range s.Tokens {
+		if strings.HasPrefix(tok.Text, "Signature Prefix Value (v): 38") {
+			want[tok.Text] = "WARNING: non-canonical RLP, the single byte 0x26 is below 0x80"
+		}
+	}
+	found := 0
+	for _, tok := range s.Tokens {
+		if prefix, ok := want[tok.Text]; ok {
+			found++
+			if !strings.HasPrefix(tok.More, prefix) {
+				t.Errorf("%q: More = %q, want prefix %q", tok.Text, tok.More, prefix)
+			}
+		}
+		if strings.HasPrefix(tok.Text, "Sender") {
+			t.Error("derived tokens for a transaction nodes would reject")
+		}
+	}
+	if found != 3 {
+		t.Errorf("found %d of the non-canonical tokens", found)
+	}
+}
+
+func TestTxHandlerBadRequest(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.SetParamNames("tx")
+	c.SetParamValues(simple[:len(simple)-2] + "zz")
+	if err := txHandler(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	var s Splain
+	if err := json.Unmarshal(rec.Body.Bytes(), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Error == nil || s.Error.Offset != 108 {
+		t.Errorf("error = %+v", s.Error)
+	}
+}
+
+func TestRLPExplain(t *testing.T) {
+	// ["cat", ["dog", 1025], []] followed by a lone byte
+	out, err := rlpExplain(common.FromHex("0xcd83636174c783646f67820401c005"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s Splain
+	if err := json.Unmarshal(out, &s); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Tokens) != 2 || len(s.Tokens[0].Children) != 4 {
+		t.Fatalf("unexpected tree %s", out)
+	}
+	if got := s.Tokens[0].Children[1].Text; got != `String (3 bytes): "cat"` {
+		t.Errorf("string token = %q", got)
+	}
+	inner := s.Tokens[0].Children[2]
+	if inner.Depth != 1 || len(inner.Children) != 4 || inner.Children[3].Depth != 2 {
+		t.Errorf("unexpected nested list %+v", inner)
+	}
+	if got := inner.Children[3].Text; got != "String (2 bytes): 0x0401" {
+		t.Errorf("integer token = %q", got)
+	}
+	if got := s.Tokens[1].Text; got != "Byte: 0x05 (5)" {
+		t.Errorf("byte token = %q", got)
+	}
+
+	// the outer list claims one more byte than there is
+	_, err = rlpExplain(common.FromHex("0xd083636174"))
+	if perr, ok := err.(*ParseError); !ok || perr.Offset != 0 {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestCalldataABI(t *testing.T) {
+	// ABIs are looked up by the recipient, DAI for dynamicFeeTx
+	abiDir = t.TempDir()
+	defer func() { abiDir = "abi" }()
+	path := filepath.Join(abiDir, "0x6b175474e89094c44da98b954eedeac495271d0f.json")
+	if err := os.WriteFile(path, []byte(daiABI), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := parseSplain(t, dynamicFeeTx, false)
+	want := []string{
+		"Function: transfer(address dst, uint256 wad)",
+		"Transfer 1000000000000000000 tokens of 0x6B175474E89094C44Da98b954EedeAC495271d0F to 0x9b0a420cD00b9D75fCE4226262789f734046E549",
+		"dst (address): 0x9b0a420cD00b9D75fCE4226262789f734046E549",
+		"wad (uint256): 1000000000000000000",
+		"Access List: empty",
+	}
+	for i, txt := range want {
+		if got := s.Tokens[9+i].Text; got != txt {
+			t.Errorf("token %d = %q, want %q", 9+i, got, txt)
+		}
+	}
+
+	// a broken ABI file falls back to the selector database, with a warning
+	if err := os.WriteFile(path, []byte("[{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s = parseSplain(t, dynamicFeeTx, false)
+	if got := s.Tokens[9].More; !strings.Contains(got, "Decoded with the offline selector database. WARNING: the ABI file "+path+" is invalid") {
+		t.Errorf("selector token = %q", got)
+	}
+
+	// an uploaded ABI, with dynamic arguments in the tail
+	batch, err := abi.JSON(strings.NewReader(batchABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x9b0a420cd00b9d75fce4226262789f734046e549")
+	data, err := batch.Pack("batch", to, []*big.Int{big.NewInt(7), big.NewInt(8)}, "gm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := callTx(t, to, data)
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/?verbose=true", strings.NewReader(url.Values{"abi": {batchABI}}.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c := e.NewContext(req, rec)
+	c.SetParamNames("tx")
+	c.SetParamValues(raw)
+	if err := txHandler(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var out Splain
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	// an ABI that doesn't parse is an error in the usual shape
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{"abi": {"[{"}}.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c = e.NewContext(req, rec)
+	c.SetParamNames("tx")
+	c.SetParamValues(raw)
+	if err := txHandler(c); err != nil {
+		t.Fatal(err)
+	}
+	var bad Splain
+	if err := json.Unmarshal(rec.Body.Bytes(), &bad); err != nil || rec.Code != http.StatusBadRequest || bad.Error == nil || !strings.HasPrefix(bad.Error.Reason, "invalid abi") {
+		t.Errorf("invalid abi: status %d, %s", rec.Code, rec.Body)
+	}
+
+	want = []string{
+		"Function: batch(address to, uint256[] ids, string memo)",
+		"to (address): 0x9b0a420cD00b9D75fCE4226262789f734046E549",
+		"ids (uint256[]): offset 96",
+		"memo (string): offset 192",
+		"ids length: 2 elements",
+		"ids[0] (uint256): 7",
+		"ids[1] (uint256): 8",
+		"memo length: 2 bytes",
+		`memo (string): "gm"`,
+	}
+	for i, tok := range out.Tokens {
+		if tok.Text != want[0] {
+			continue
+		}
+		for j, txt := range want {
+			if got := out.Tokens[i+j]; got.Text != txt || got.Field != "input" {
+				t.Errorf("token %d = %q in %q, want %q", i+j, got.Text, got.Field, txt)
+			}
+		}
+		if got := out.Tokens[i-1].Text; !strings.HasPrefix(got, "RLP Length Prefix") {
+			t.Errorf("token before the selector = %q", got)
+		}
+		return
+	}
+	t.Error("calldata not decoded")
+}
+
+func TestSelectorDatabase(t *testing.T) {
+	s := parseSplain(t, accessListTx, false)
+	if got := s.Tokens[8].Text; got != "Function: transfer(address, uint256)" {
+		t.Errorf("selector token = %q", got)
+	}
+	if got := s.Tokens[10].Text; got != "arg0 (address): 0x9b0a420cD00b9D75fCE4226262789f734046E549" {
+		t.Errorf("argument token = %q", got)
+	}
+
+	// burn(uint256) and collate_propagate_storage(bytes16) share 0x42966c68
+	to := common.HexToAddress("0x6b175474e89094c44da98b954eedeac495271d0f")
+	for _, tc := range []struct {
+		data string
+		text string
+	}{
+		{"0x42966c680000000000000000000000000000000100000000000000000000000000000000", "Function: burn(uint256) or collate_propagate_storage(bytes16)"},
+		{"0x42966c680000000000000000000000000000000000000000000000000000000000000001", "Function: burn(uint256)"},
+		{"0xa9059cbb010203", "Data: calls transfer(address,uint256) with 0x010203"},
+	} {
+		raw := callTx(t, to, common.FromHex(tc.data))
+		s := parseSplain(t, raw, false)
+		if got := s.Tokens[9].Text; got != tc.text {
+			t.Errorf("%s: data token = %q, want %q", tc.data, got, tc.text)
+		}
+	}
+
+	if err := loadSelectors(strings.NewReader("0x12345678 transfer(address,uint256)")); err == nil {
+		t.Error("selector that doesn't match its signature was loaded")
+	}
+	if err := loadSelectors(strings.NewReader(fmt.Sprintf("0x%x foo(notatype)", crypto.Keccak256([]byte("foo(notatype)"))[:4]))); err == nil {
+		t.Error("signature with an invalid type was loaded")
+	}
+}
+
+func TestTokenCalls(t *testing.T) {
+	token := common.HexToAddress("0x6b175474e89094c44da98b954eedeac495271d0f")
+	other := common.HexToAddress("0x9b0a420cd00b9d75fce4226262789f734046e549")
+	for _, tc := range []struct {
+		sig  string
+		args []interface{}
+		text string
+	}{
+		{"approve(address,uint256)", []interface{}{other, maxUint256}, "Approve 0x9b0a420cD00b9D75fCE4226262789f734046E549 to spend UNLIMITED tokens of 0x6B175474E89094C44Da98b954EedeAC495271d0F"},
+		{"approve(address,uint256)", []interface{}{other, big.NewInt(0)}, "Revoke the approval of 0x9b0a420cD00b9D75fCE4226262789f734046E549 to spend tokens of 0x6B175474E89094C44Da98b954EedeAC495271d0F"},
+		{"setApprovalForAll(address,bool)", []interface{}{other, true}, "Approve 0x9b0a420cD00b9D75fCE4226262789f734046E549 to transfer ALL tokens of 0x6B175474E89094C44Da98b954EedeAC495271d0F"},
+		{"safeTransferFrom(address,address,uint256)", []interface{}{token, other, big.NewInt(42)}, "Transfer NFT #42 of 0x6B175474E89094C44Da98b954EedeAC495271d0F from 0x6B175474E89094C44Da98b954EedeAC495271d0F to 0x9b0a420cD00b9D75fCE4226262789f734046E549"},
+		{"safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)", []interface{}{token, other, []*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(5), big.NewInt(6)}, []byte{}}, "Transfer 5 of token ID 1, 6 of token ID 2 of 0x6B175474E89094C44Da98b954EedeAC495271d0F from 0x6B175474E89094C44Da98b954EedeAC495271d0F to 0x9b0a420cD00b9D75fCE4226262789f734046E549"},
+	} {
+		method, err := parseSignature(tc.sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args, err := method.Inputs.Pack(tc.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw := callTx(t, token, append(method.ID, args...))
+		s := parseSplain(t, raw, false)
+		if got := s.Tokens[10]; got.Text != tc.text || got.Hex != "" {
+			t.Errorf("%s: sentence token = %q", tc.sig, got.Text)
+		}
+	}
+}
+
+func TestNestedCalls(t *testing.T) {
+	dai := common.HexToAddress("0x6b175474e89094c44da98b954eedeac495271d0f")
+	multicall := common.HexToAddress("0xca11bde05977b3631167028862be2a173976ca11")
+	safe := common.HexToAddress("0x9b0a420cd00b9d75fce4226262789f734046e549")
+
+	// a safe executing a multicall3 aggregate3 of a transfer and an approval
+	transfer, _ := parseSignature("transfer(address,uint256)")
+	approve, _ := parseSignature("approve(address,uint256)")
+	transferArgs, _ := transfer.Inputs.Pack(safe, big.NewInt(5))
+	approveArgs, _ := approve.Inputs.Pack(safe, maxUint256)
+	type call3 struct {
+		Target       common.Address
+		AllowFailure bool
+		CallData     []byte
+	}
+	m, err := abi.JSON(strings.NewReader(multicallABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aggregate, err := m.Pack("aggregate3", []call3{
+		{dai, false, append(transfer.ID, transferArgs...)},
+		{dai, true, append(approve.ID, approveArgs...)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec, _ := parseSignature("execTransaction(address,uint256,bytes,uint8,uint256,uint256,uint256,address,address,bytes)")
+	execArgs, err := exec.Inputs.Pack(multicall, big.NewInt(0), aggregate, uint8(1), big.NewInt(0), big.NewInt(0), big.NewInt(0), common.Address{}, common.Address{}, []byte{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := callTx(t, safe, append(exec.ID, execArgs...))
+
+	find := func(tokens []Token, prefix string) *Token {
+		for i := range tokens {
+			if strings.HasPrefix(tokens[i].Text, prefix) {
+				return &tokens[i]
+			}
+		}
+		t.Fatalf("no %q token", prefix)
+		return nil
+	}
+	s := parseSplain(t, raw, false)
+	outer := find(s.Tokens, "arg2 (bytes): Call")
+	if outer.Text != "arg2 (bytes): Call 0xcA11bde05977b3631167028862bE2a173976CA11: aggregate3((address,bool,bytes)[])" {
+		t.Errorf("outer call = %q", outer.Text)
+	}
+	inner := find(outer.Children, "arg0[1].2 (bytes): Call")
+	if inner.Text != "arg0[1].2 (bytes): Call 0x6B175474E89094C44Da98b954EedeAC495271d0F: approve(address, uint256)" {
+		t.Errorf("inner call = %q", inner.Text)
+	}
+	if got := inner.Children[1].Text; got != "Approve 0x9b0a420cD00b9D75fCE4226262789f734046E549 to spend UNLIMITED tokens of 0x6B175474E89094C44Da98b954EedeAC495271d0F" {
+		t.Errorf("inner call summary = %q", got)
+	}
+	// children point at their own bytes of the raw input
+	rawHex := strings.TrimPrefix(raw, "0x")
+	for _, tok := range append(outer.Children, inner.Children...) {
+		if rawHex[tok.Offset*2:tok.Offset*2+len(tok.Hex)] != tok.Hex {
+			t.Errorf("%q is not at offset %d", tok.Text, tok.Offset)
+		}
+	}
+
+	maxCallDepth = 1
+	defer func() { maxCallDepth = 4 }()
+	s = parseSplain(t, raw, false)
+	if inner := find(find(s.Tokens, "arg2 (bytes): Call").Children, "arg0[1].2 (bytes): 0x"); inner.Children != nil {
+		t.Errorf("call decoded beyond the maximum depth: %q", inner.Text)
+	}
+
+	// multicalls whose elements all point at the same bytes overlap, and are rejected before their
+	// elements are decoded
+	data := append(transfer.ID, transferArgs...)
+	for level := 0; level < 5; level++ {
+		const n = 16
+		words := []*big.Int{big.NewInt(32), big.NewInt(n)}
+		for i := 0; i < n; i++ {
+			words = append(words, big.NewInt(n*32))
+		}
+		words = append(words, big.NewInt(int64(len(data))))
+		next := common.FromHex("0xac9650d8")
+		for _, w := range words {
+			next = append(next, common.LeftPadBytes(w.Bytes(), 32)...)
+		}
+		data = append(next, common.RightPadBytes(data, (len(data)+31)/32*32)...)
+	}
+	splain := Splain{to: multicall.Bytes()}
+	if call := splain.decodeCall(data, multicall.Bytes(), 0); call != nil || splain.abiParts > 100 {
+		t.Errorf("aliased multicall decoded %d parts", splain.abiParts)
+	}
+
+	// the uploaded ABI describes the recipient, not the contracts it calls
+	d, err := abi.JSON(strings.NewReader(daiABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	splain = Splain{to: safe.Bytes(), abi: &d}
+	for to, want := range map[common.Address]string{safe: "the ABI uploaded with the request", dai: "the offline selector database"} {
+		call := splain.decodeCall(append(transfer.ID, transferArgs...), to.Bytes(), 1)
+		if call == nil || !strings.HasSuffix(call[0].more, "Decoded with "+want) {
+			t.Errorf("call to %s not decoded with %s", to.Hex(), want)
+		}
+	}
+}
+
+func TestInitCode(t *testing.T) {
+	s := parseSplain(t, contract, true)
+	var texts []string
+	var runtime Token
+	for _, tok := range s.Tokens {
+		texts = append(texts, tok.Text)
+		if strings.HasPrefix(tok.Text, "Runtime Code") {
+			runtime = tok
+		}
+	}
+	all := strings.Join(texts, "\n")
+	for _, want := range []string{
+		"PUSH1 0x80\nPUSH1 0x40\nMSTORE\nCALLVALUE",
+		"CODECOPY: copies the 788 byte runtime code at offset 82 of the init code to memory\nPUSH1 0x00\nRETURN: returns the runtime code, the code of the new contract\nSTOP\nRuntime Code: 788 bytes, metadata bzz-raw://e15ee5e2160fdc89ce720dc909c6dc0f003d58418735db64a66e99fd3338afa3\nConstructor Argument 0: 0x98d0C1a1045a3145eA8d06F1db575819C8A7c9Bd",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("init code tokens don't contain %q", want)
+		}
+	}
+	if len(runtime.Children) == 0 || runtime.Children[0].Text != "PUSH1 0x80" || runtime.Children[0].Offset != runtime.Offset {
+		t.Errorf("runtime code not disassembled: %+v", runtime.Children)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	// the trailer solc 0.8.24 appends, with a made up ipfs hash
+	code := common.FromHex("0x6080604052fe" + "a2646970667358221220" + strings.Repeat("5f", 32) + "64736f6c63430008180033")
+	tokens, summary, end := metadataTokens(code, 100)
+	if summary != "compiled with solc 0.8.24, metadata ipfs://QmUkwnhkEUxKh9rFBPUahmJL3ThZqidgnbA5LCEGfEBLiA" {
+		t.Errorf("summary = %q", summary)
+	}
+	if end != 6 {
+		t.Errorf("code ends at %d, want 6", end)
+	}
+	want := []string{
+		"Metadata: a CBOR map of 2 entries",
+		"Metadata Hash: ipfs://QmUkwnhkEUxKh9rFBPUahmJL3ThZqidgnbA5LCEGfEBLiA",
+		"Compiler: solc 0.8.24",
+		"Metadata Length: 51 bytes",
+	}
+	pos := 106
+	for i, tok := range tokens {
+		if tok.Text != want[i] || tok.Offset != pos {
+			t.Errorf("token %d = %q at %d, want %q at %d", i, tok.Text, tok.Offset, want[i], pos)
+		}
+		pos += tok.Length
+	}
+	if pos != 100+len(code) {
+		t.Errorf("metadata tokens end at %d, want %d", pos, 100+len(code))
+	}
+
+	// code without metadata
+	if tokens, _, end := metadataTokens(common.FromHex("0x6080604052"), 0); tokens != nil || end != 5 {
+		t.Errorf("metadata found in code without any: %v", tokens)
+	}
+}
+
+func TestContractAddress(t *testing.T) {
+	s := parseSplain(t, contract, false)
+	if got := s.Tokens[5].Text; got != "Contract Address: 0x2e7DA3a9eF1Ad95472aEC9fE1B013a141318d871" {
+		t.Errorf("contract address token = %q", got)
+	}
+	if !strings.HasSuffix(s.Tokens[5].More, "[sender, nonce]: 0xd894a9a8bdaefb34ea98be19badb76bb68613641247382265a") {
+		t.Errorf("contract address preimage = %q", s.Tokens[5].More)
+	}
+	// verbose, the recipient's rlp prefix has its own token before it
+	s = parseSplain(t, contract, true)
+	for i, tok := range s.Tokens {
+		if tok.Text == "Recipient Address: 0x0" && s.Tokens[i+1].Field != "contractAddress" {
+			t.Errorf("recipient is followed by %q", s.Tokens[i+1].Text)
+		}
+	}
+
+	// salt and init code sent to the deterministic deployment proxy
+	proxy := common.HexToAddress("0x4e59b44847b379578588920ca78fbf26c0b4956c")
+	salt := common.HexToHash("0x01")
+	initCode := common.FromHex("0x600a600c600039600a6000f3602a60005260206000f3")
+	raw := callTx(t, proxy, append(salt.Bytes(), initCode...))
+	s = parseSplain(t, raw, false)
+	want := "CREATE2 Address: " + crypto.CreateAddress2(proxy, salt, crypto.Keccak256(initCode)).Hex()
+	if got := s.Tokens[10]; got.Text != want || got.Field != "create2Address" {
+		t.Errorf("create2 token = %q, want %q", got.Text, want)
+	}
+}
+
+func TestUnits(t *testing.T) {
+	for _, tc := range []struct {
+		wei  string
+		want string
+	}{
+		{"1", "0.000000000000000001 ETH (1 wei)"},
+		{"1000000000000000000", "1 ETH (1000000000000000000 wei)"},
+		{"123456789012345678901234567890", "123456789012.34567890123456789 ETH (123456789012345678901234567890 wei)"},
+	} {
+		wei, _ := new(big.Int).SetString(tc.wei, 10)
+		if got := etherAmount(wei); got != tc.want {
+			t.Errorf("etherAmount(%s) = %q, want %q", tc.wei, got, tc.want)
+		}
+	}
+	if got := gweiAmount(big.NewInt(1500000001)); got != "1.500000001 gwei (1500000001 wei)" {
+		t.Errorf("gweiAmount = %q", got)
+	}
+
+	prices["USD"] = big.NewRat(250012, 100)
+	defer delete(prices, "USD")
+	s := parseSplain(t, simple, false)
+	if got := s.Tokens[5].Text; got != "Value: 0.000681664583147611 ETH (681664583147611 wei, 1.70 USD)" {
+		t.Errorf("value token = %q", got)
+	}
+}
+
+func TestGas(t *testing.T) {
+	for _, tc := range []struct {
+		raw       string
+		intrinsic string
+		maxFee    string
+	}{
+		{simple, "Intrinsic Gas: 21000", "Max Fee: 0.000105 ETH (105000000000000 wei)"},
+		{contract, "Intrinsic Gas: 66230", "Max Fee: 0.00285843 ETH (2858430000000000 wei)"},
+		{accessListTx, "Intrinsic Gas: 27832", "Max Fee: 0.0012 ETH (1200000000000000 wei)"},
+		{blobTx, "Intrinsic Gas: 21000", "Max Fee: 0.000761072 ETH (761072000000000 wei)"},
+		{setCodeTx, "Intrinsic Gas: 46000", "Max Fee: 0.003 ETH (3000000000000000 wei)"},
+	} {
+		got := map[string]Token{}
+		for _, tok := range parseSplain(t, tc.raw, false).Tokens {
+			got[tok.Field] = tok
+		}
+		if got["intrinsicGas"].Text != tc.intrinsic || got["maxFee"].Text != tc.maxFee {
+			t.Errorf("got %q and %q, want %q and %q", got["intrinsicGas"].Text, got["maxFee"].Text, tc.intrinsic, tc.maxFee)
+		}
+	}
+
+	// 21000 gas doesn't cover the data
+	raw := signedTx(t, &types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, To: &common.Address{1}, Value: big.NewInt(1e18), Data: []byte{1, 2, 3}})
+	for _, tok := range parseSplain(t, raw, false).Tokens {
+		switch tok.Field {
+		case "intrinsicGas":
+			if !strings.HasPrefix(tok.More, "WARNING: the gas limit 21000 is below the 21120 gas") {
+				t.Errorf("intrinsic gas = %q", tok.More)
+			}
+		case "maxCost":
+			if tok.Text != "Max Total Cost: 1.000000000000021 ETH (1000000000000021000 wei)" {
+				t.Errorf("max cost = %q", tok.Text)
+			}
+		}
+	}
+}
+
+func TestBaseFee(t *testing.T) {
+	// dynamicFeeTx offers at most 30 gwei with a 2 gwei tip
+	get := func(query string) (int, Splain) {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?"+query, nil), rec)
+		c.SetParamNames("tx")
+		c.SetParamValues(dynamicFeeTx)
+		if err := txHandler(c); err != nil {
+			t.Fatal(err)
+		}
+		var out Splain
+		if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, out
+	}
+	fields := func(query string) map[string]string {
+		code, out := get(query)
+		if code != http.StatusOK {
+			t.Fatalf("status = %d: %v", code, out.Error)
+		}
+		got := map[string]string{}
+		for _, tok := range out.Tokens {
+			got[tok.Field] = tok.Text
+		}
+		return got
+	}
+
+	got := fields("basefee=29gwei")
+	for field, want := range map[string]string{
+		"effectiveGasPrice":    "Effective Gas Price: 30 gwei (30000000000 wei)",
+		"effectivePriorityFee": "Priority Fee Paid: 1 gwei (1000000000 wei)",
+		"burnedFee":            "Burned Fee: 0.00174 ETH (1740000000000000 wei)",
+	} {
+		if got[field] != want {
+			t.Errorf("%s = %q, want %q", field, got[field], want)
+		}
+	}
+
+	// the flag is the default, and above the max fee the transaction can't be included
+	baseFee = big.NewInt(31000000000)
+	defer func() { baseFee = nil }()
+	got = fields("")
+	if _, ok := got["effectiveGasPrice"]; ok || got["baseFee"] != "Base Fee: 31 gwei (31000000000 wei)" {
+		t.Errorf("got %v", got)
+	}
+
+	for _, fee := range []string{"1.5", "-1", "0.0000000001gwei", "many"} {
+		if _, err := parseBaseFee(fee); err == nil {
+			t.Errorf("parseBaseFee(%q) succeeded", fee)
+		}
+	}
+	if code, out := get("basefee=many"); code != http.StatusBadRequest || out.Error == nil || !strings.HasPrefix(out.Error.Reason, "invalid base fee") {
+		t.Errorf("invalid base fee: status %d, error %v", code, out.Error)
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	for _, tc := range []struct {
+		addr string
+		want string
+	}{
+		{"0x00000000219ab540356cbb839cbe05303d7705fa", "Recipient Address: 0x00000000219ab540356cBB839Cbe05303d7705Fa (the beacon chain deposit contract)"},
+		{"0x000f3df6d732807ef1319fb7b8bb8522d0beac02", "Recipient Address: 0x000F3df6D732807Ef1319fB7B8bB8522d0Beac02 (the EIP-4788 beacon roots contract)"},
+		{"0x0000000000000000000000000000000000000001", "Recipient Address: 0x0000000000000000000000000000000000000001 (the ecrecover precompile)"},
+		{"0x0000000000000000000000000000000000000100", "Recipient Address: 0x0000000000000000000000000000000000000100 (the p256Verify precompile)"},
+		{"0x0000000000000000000000000000000000000000", "Recipient Address: 0x0000000000000000000000000000000000000000 (the zero address)"},
+		{"0x000000000000000000000000000000000000dead", "Recipient Address: 0x000000000000000000000000000000000000dEaD (a burn address)"},
+		{"0x9b0a420cd00b9d75fce4226262789f734046e549", "Recipient Address: 0x9b0a420cD00b9D75fCE4226262789f734046E549"},
+	} {
+		txt, more := recipientInfo(common.FromHex(tc.addr), false)
+		if txt != tc.want {
+			t.Errorf("got %q, want %q", txt, tc.want)
+		}
+		if (more == shortTo) != !strings.Contains(tc.want, "(") {
+			t.Errorf("%s: explained as %q", tc.addr, more)
+		}
+	}
+
+	get := func(addr string) (int, Splain) {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames("address")
+		c.SetParamValues(addr)
+		if err := addressHandler(c); err != nil {
+			t.Fatal(err)
+		}
+		var out Splain
+		if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, out
+	}
+	for addr, warn := range map[string]bool{
+		"0x9b0a420cD00b9D75fCE4226262789f734046E549": false,
+		"0x9b0a420cd00b9d75fce4226262789f734046e549": false,
+		"0x9B0A420CD00B9D75FCE4226262789F734046E549": false,
+		"0x9b0a420cD00b9D75fCE4226262789f734046e549": true,
+	} {
+		code, out := get(addr)
+		if code != http.StatusOK || len(out.Tokens) != 1 {
+			t.Fatalf("%s: status %d, %d tokens", addr, code, len(out.Tokens))
+		}
+		if got := strings.HasPrefix(out.Tokens[0].More, "WARNING: the case of the address doesn't match its EIP-55 checksum, it should be 0x9b0a420cD00b9D75fCE4226262789f734046E549"); got != warn {
+			t.Errorf("%s: checksum warning %v, want %v", addr, got, warn)
+		}
+	}
+	if code, out := get("0x9b0a420c"); code != http.StatusBadRequest || out.Error == nil {
+		t.Errorf("short address: status %d, error %v", code, out.Error)
+	}
+}
+
+func signedTx(t *testing.T, inner types.TxData) string {
+	key, _ := crypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hexutil.Encode(raw)
+}
+
+// callTx signs a dynamic fee transaction sending data to the contract to
+func callTx(t *testing.T, to common.Address, data []byte) string {
+	return signedTx(t, &types.DynamicFeeTx{ChainID: big.NewInt(1), GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 100000, To: &to, Data: data})
+}
+
+func parseSplain(t *testing.T, raw string, verbose bool) Splain {
+	out, err := parse(raw, verbose)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s Splain
+	if err := json.Unmarshal(out, &s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// signed without EIP-155 replay protection
+var homesteadTx = "0xf86c038504a817c800825208949b0a420cd00b9d75fce4226262789f734046e549880de0b6b3a7640000801ca04bb1ffad8d6051b28ab8bbde3e3393c75495a41ad52fdcec2a081a4369c80dc8a026c474eef42a0905ce95e0d03ebfbdd67999ef298a0903606ae197c6938f854d"
+
+var daiABI = `[{"constant":false,"inputs":[{"name":"dst","type":"address"},{"name":"wad","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"}]`
+
+var batchABI = `[{"inputs":[{"name":"to","type":"address"},{"name":"ids","type":"uint256[]"},{"name":"memo","type":"string"}],"name":"batch","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+var multicallABI = `[{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[],"stateMutability":"payable","type":"function"}]`
+
+// typed transactions signed on mainnet (chain id 1) by 0x2c7536E3605D9C16a7a3D7b1898e529396a65c23
+var accessListTx = "0x01f9010701078504a817c80082ea60946b175474e89094c44da98b954eedeac495271d0f80b844a9059cbb0000000000000000000000009b0a420cd00b9d75fce4226262789f734046e5490000000000000000000000000000000000000000000000000de0b6b3a7640000f85bf859946b175474e89094c44da98b954eedeac495271d0ff842a00000000000000000000000000000000000000000000000000000000000000001a0000000000000000000000000000000000000000000000000000000000000000201a0eb449c57ed95b73c29b92bf06ecc16414b12274f4c319c192eed1ce2e16c0a37a015536f1a1fcb1a482ab6126f6d637665240f248d44d842add76e8774adb7696b"
+
+var dynamicFeeTx = "0x02f8b0010784773594008506fc23ac0082ea60946b175474e89094c44da98b954eedeac495271d0f80b844a9059cbb0000000000000000000000009b0a420cd00b9d75fce4226262789f734046e5490000000000000000000000000000000000000000000000000de0b6b3a7640000c080a0af10c47851543bac69318fb120000ccb1ac0182ae35c69983005deea848267a7a019b65be0a642caa8ac46ed235540a114df821fffa376a6e785ab50987b9d71e3"
+
+var blobTx = "0x03f892010784773594008506fc23ac00825208946b175474e89094c44da98b954eedeac495271d0f8080c0843b9aca00e1a00148729599c6d30577f6709cfd8f99af4eadee5abfaff00e1cfbfeb26a80506f80a0b1f0d87f6d9d1956a45347ac18325f11be1a912dfd96c496afa96d120cabb81fa05b2d67b7cd24079adf990e92043b671e734df5f09718549fad156134806c1cdc"
+
+var setCodeTx = "0x04f8ca010784773594008506fc23ac00830186a0942c7536e3605d9c16a7a3d7b1898e529396a65c238080c0f85cf85a019463c0c19a282a1b52b07dd5a65b58948a07dae32b0801a09c23b06d5bdcd69ee74e86ce1eb7795daa4610f16e29441d4b569580bc799cc0a04f659699da485031c18c20eb15c9d0b98e98d9280c08cfe1fa7ff452f3673ceb01a0eba7be85075beb7686aaede519a6bdf0f4ab32bb33a0e9be775ff603ebeef6faa0478becb1a78ea80a7cd0db7e87168bcfc75adeae3a01390fff493baa4efe7cb3"
+
 var simple = "0xf86b8085012a05f200825208949b0a420cd00b9d75fce4226262789f734046e54987026bf86755a05b8026a06a49585b2e6720633828f7a55e5f98709d9f6f4bfe869c9f5616ce46eb26566aa0751d23163c267e0f141481964100620f3f228da1636fe90129687425d8a8f836"
 
 var simpleExpected = `{
@@ -26,52 +1048,155 @@ var simpleExpected = `{
 		{
 			"Hex": "f86b",
 			"Text": "RLP Prefix. Tells us that this transaction is a list of length 0x6b (107 bytes)",
-			"More": "The first byte (0xf8-0xf7) tells us the length of the length (0x6b) of transaction"
+			"More": "The first byte (0xf8-0xf7) tells us the length of the length (0x6b) of transaction. A first byte between 0xf8 and 0xff is a long list of more than 55 bytes, followed by its length as a 1 byte big endian number",
+			"Offset": 0,
+			"Length": 2,
+			"Field": "transaction",
+			"Parent": -1
 		},
 		{
 			"Hex": "80",
 			"Text": "Nonce: 0",
-			"More": "The nonce is an incrementing sequence number used to prevent message replay"
+			"More": "The nonce is an incrementing sequence number used to prevent message replay",
+			"Offset": 2,
+			"Length": 1,
+			"Field": "nonce",
+			"Parent": 0
 		},
 		{
 			"Hex": "85012a05f200",
-			"Text": "Gas Price: 5000000000",
-			"More": "The price of gas (in wei) that the sender is willing to pay."
+			"Text": "Gas Price: 5 gwei (5000000000 wei)",
+			"More": "The price of gas (in wei) that the sender is willing to pay.",
+			"Offset": 3,
+			"Length": 6,
+			"Field": "gasPrice",
+			"Parent": 0
 		},
 		{
 			"Hex": "825208",
 			"Text": "Gas Limit: 21000",
-			"More": "The maximum amount of gas the originator is willing to pay for this transaction."
+			"More": "The maximum amount of gas the originator is willing to pay for this transaction.",
+			"Offset": 9,
+			"Length": 3,
+			"Field": "gas",
+			"Parent": 0
 		},
 		{
 			"Hex": "949b0a420cd00b9d75fce4226262789f734046e549",
-			"Text": "Recipient Address: 0x9b0a420cd00b9d75fce4226262789f734046e549",
-			"More": "The address of the user account or contract to interact with"
+			"Text": "Recipient Address: 0x9b0a420cD00b9D75fCE4226262789f734046E549",
+			"More": "The address of the user account or contract to interact with",
+			"Offset": 12,
+			"Length": 21,
+			"Field": "to",
+			"Parent": 0
 		},
 		{
 			"Hex": "87026bf86755a05b",
-			"Text": "Value: 681664583147611",
-			"More": "The amount of ether (in wei) to send to the recipient address."
+			"Text": "Value: 0.000681664583147611 ETH (681664583147611 wei)",
+			"More": "The amount of ether (in wei) to send to the recipient address.",
+			"Offset": 33,
+			"Length": 8,
+			"Field": "value",
+			"Parent": 0
 		},
 		{
 			"Hex": "80",
 			"Text": "Data: ",
-			"More": "Data being sent to a contract function. The first 4 bytes are known as the 'function selector'"
+			"More": "Data being sent to a contract function. The first 4 bytes are known as the 'function selector'",
+			"Offset": 41,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
 		},
 		{
 			"Hex": "26",
-			"Text": "Signature Prefix Value (v): 26",
-			"More": "Indicates both the chainID of the transaction and the parity (odd or even) of the y component of the public key"
+			"Text": "Signature Prefix Value (v): 38 = chain ID 1 (Mainnet), y parity 1",
+			"More": "Indicates both the chainID of the transaction and the parity (odd or even) of the y component of the public key. EIP-155 encodes v = chainId * 2 + 35 + parity = 1 * 2 + 35 + 1, which ties the signature to one chain and protects it from replay on other chains",
+			"Offset": 42,
+			"Length": 1,
+			"Field": "v",
+			"Parent": 0
 		},
 		{
 			"Hex": "a06a49585b2e6720633828f7a55e5f98709d9f6f4bfe869c9f5616ce46eb26566a",
 			"Text": "Signature (r) value: 6a49585b2e6720633828f7a55e5f98709d9f6f4bfe869c9f5616ce46eb26566a",
-			"More": "Part of the signature pair (r,s). Represents the X-coordinate of an ephemeral public key created during the ECDSA signing process"
+			"More": "Part of the signature pair (r,s). Represents the X-coordinate of an ephemeral public key created during the ECDSA signing process",
+			"Offset": 43,
+			"Length": 33,
+			"Field": "r",
+			"Parent": 0
 		},
 		{
 			"Hex": "a0751d23163c267e0f141481964100620f3f228da1636fe90129687425d8a8f836",
 			"Text": "Signature (s) value: 751d23163c267e0f141481964100620f3f228da1636fe90129687425d8a8f836",
-			"More": "Part of the signature pair (r,s). Generated using the ECDSA signing algorithm"
+			"More": "Part of the signature pair (r,s). Generated using the ECDSA signing algorithm",
+			"Offset": 76,
+			"Length": 33,
+			"Field": "s",
+			"Parent": 0
+		},
+		{
+			"Hex": "",
+			"Text": "Intrinsic Gas: 21000",
+			"More": "Derived, not part of the raw transaction. The gas charged before any code runs: 21000 base",
+			"Offset": 109,
+			"Length": 0,
+			"Field": "intrinsicGas",
+			"Parent": -1
+		},
+		{
+			"Hex": "",
+			"Text": "Max Fee: 0.000105 ETH (105000000000000 wei)",
+			"More": "Derived, not part of the raw transaction. The most the transaction can pay for gas, the Gas Limit 21000 × the Gas Price 5 gwei (5000000000 wei)",
+			"Offset": 109,
+			"Length": 0,
+			"Field": "maxFee",
+			"Parent": -1
+		},
+		{
+			"Hex": "",
+			"Text": "Max Total Cost: 0.000786664583147611 ETH (786664583147611 wei)",
+			"More": "Derived, not part of the raw transaction. The maximum fee plus the value sent. Nodes only accept the transaction if the sender's balance covers all of it",
+			"Offset": 109,
+			"Length": 0,
+			"Field": "maxCost",
+			"Parent": -1
+		},
+		{
+			"Hex": "",
+			"Text": "Transaction Hash: 0xc175cb284dde4b0c304e2ad7e58a6431f8b65ece51bd21f058c761a53213e34d",
+			"More": "Derived, not part of the raw transaction. The keccak256 hash of the signed transaction (including the type byte of typed transactions). This is the ID used to look the transaction up in a block explorer",
+			"Offset": 109,
+			"Length": 0,
+			"Field": "hash",
+			"Parent": -1
+		},
+		{
+			"Hex": "",
+			"Text": "Signing Preimage: 0xeb8085012a05f200825208949b0a420cd00b9d75fce4226262789f734046e54987026bf86755a05b80018080",
+			"More": "Derived, not part of the raw transaction. The exact bytes the sender signed using the EIP-155 (chain 1) scheme. It is the RLP list [nonce, gasPrice, gasLimit, to, value, data, chainId, 0, 0]. EIP-155 replaces v, r and s with the chain ID and two zeros, so the signature is only valid on this chain",
+			"Offset": 109,
+			"Length": 0,
+			"Field": "signingPreimage",
+			"Parent": -1
+		},
+		{
+			"Hex": "",
+			"Text": "Signing Hash: 0x893361ba9f83d7beb8f0041051c397d8fe32ba38db8446857e91f2c9e27bcb04",
+			"More": "Derived, not part of the raw transaction. The keccak256 hash of the signing preimage. This 32 byte hash is what the ECDSA signature (v, r, s) actually signs",
+			"Offset": 109,
+			"Length": 0,
+			"Field": "signingHash",
+			"Parent": -1
+		},
+		{
+			"Hex": "",
+			"Text": "Sender: 0x0f797B5bC66a2F4759542b2b3803438e4537662D",
+			"More": "Derived, not part of the raw transaction. Recovered from the signature (r, s and the y parity) and the EIP-155 (chain 1) signing hash. Public key: 0x040aae84afe2eca8323badd1610a9d801f77b107a54d120f26ed2b5c7d6bbf17d9f16df0cf959c4587bea8c2b7b1a7de66df7edef30feaa611143eddb7a11c8fee",
+			"Offset": 109,
+			"Length": 0,
+			"Field": "sender",
+			"Parent": -1
 		}
 	]
 }`
@@ -82,53 +1207,737 @@ var contractExpected = `{
 	"Tokens": [
 		{
 			"Hex": "f903db",
-			"Text": "RLP Prefix. Tells us that this transaction is a list of length 0x03db (3 bytes)",
-			"More": "The first byte (0xf9-0xf7) tells us the length of the length (0x03db) of transaction"
+			"Text": "RLP Prefix. Tells us that this transaction is a list of length 0x03db (987 bytes)",
+			"More": "The first byte (0xf9-0xf7) tells us the length of the length (0x03db) of transaction. A first byte between 0xf8 and 0xff is a long list of more than 55 bytes, followed by its length as a 2 byte big endian number",
+			"Offset": 0,
+			"Length": 3,
+			"Field": "transaction",
+			"Parent": -1
 		},
 		{
 			"Hex": "82265a",
 			"Text": "Nonce: 9818",
-			"More": "The nonce is an incrementing sequence number used to prevent message replay"
+			"More": "The nonce is an incrementing sequence number used to prevent message replay",
+			"Offset": 3,
+			"Length": 3,
+			"Field": "nonce",
+			"Parent": 0
 		},
 		{
 			"Hex": "8502540be400",
-			"Text": "Gas Price: 10000000000",
-			"More": "The price of gas (in wei) that the sender is willing to pay."
+			"Text": "Gas Price: 10 gwei (10000000000 wei)",
+			"More": "The price of gas (in wei) that the sender is willing to pay.",
+			"Offset": 6,
+			"Length": 6,
+			"Field": "gasPrice",
+			"Parent": 0
 		},
 		{
 			"Hex": "83045c93",
 			"Text": "Gas Limit: 285843",
-			"More": "The maximum amount of gas the originator is willing to pay for this transaction."
+			"More": "The maximum amount of gas the originator is willing to pay for this transaction.",
+			"Offset": 12,
+			"Length": 4,
+			"Field": "gas",
+			"Parent": 0
 		},
 		{
 			"Hex": "80",
 			"Text": "Recipient Address: 0x0",
-			"More": "This transaction is a special type of transaction for Contract Creation. Notice the address is the Zero Address 0x0"
+			"More": "This transaction is a special type of transaction for Contract Creation. Notice the address is the Zero Address 0x0",
+			"Offset": 16,
+			"Length": 1,
+			"Field": "to",
+			"Parent": 0
+		},
+		{
+			"Hex": "",
+			"Text": "Contract Address: 0x2e7DA3a9eF1Ad95472aEC9fE1B013a141318d871",
+			"More": "Derived, not part of the raw transaction. The address of the contract this transaction creates, the last 20 bytes of the Keccak-256 hash of the RLP list [sender, nonce]: 0xd894a9a8bdaefb34ea98be19badb76bb68613641247382265a",
+			"Offset": 17,
+			"Length": 0,
+			"Field": "contractAddress",
+			"Parent": 0
 		},
 		{
 			"Hex": "80",
-			"Text": "Value: 0",
-			"More": "The amount of ether (in wei) to send to the recipient address."
+			"Text": "Value: 0 ETH",
+			"More": "The amount of ether (in wei) to send to the recipient address.",
+			"Offset": 17,
+			"Length": 1,
+			"Field": "value",
+			"Parent": 0
+		},
+		{
+			"Hex": "b903866080",
+			"Text": "PUSH1 0x80",
+			"More": "Pushes the next 1 bytes of code. This is a contract creation, the data is the init code. The EVM runs it once and stores what it returns as the code of the new contract",
+			"Offset": 18,
+			"Length": 5,
+			"Field": "input",
+			"Parent": 0
 		},
 		{
-			"Hex": "b90386608060405234801561001057600080fd5b50604051602080610366833981016040525160008054600160a060020a03909216600160a060020a0319909216919091179055610314806100526000396000f30060806040526004361061004b5763ffffffff7c01000000000000000000000000000000000000000000000000000000006000350416633f579f42811461004d578063d2ec4a92146100b6575b005b34801561005957600080fd5b50604080516020600460443581810135601f810184900484028501840190955284845261004b948235600160a060020a03169460248035953695946064949201919081908401838280828437509497506100e79650505050505050565b3480156100c257600080fd5b506100cb6102d9565b60408051600160a060020a039092168252519081900360200190f35b6000809054906101000a9004600160a060020a0316600160a060020a031663c34c08e56040518163ffffffff167c0100000000000000000000000000000000000000000000000000000000028152600401602060405180830381600087803b15801561015257600080fd5b505af1158015610166573d6000803e3d6000fd5b505050506040513d602081101561017c57600080fd5b5051600160a060020a0316331461019257600080fd5b82600160a060020a0316828260405180828051906020019080838360005b838110156101c85781810151838201526020016101b0565b50505050905090810190601f1680156101f55780820380516001836020036101000a031916815260200191505b5091505060006040518083038185875af192505050156102cf577f39f46e1dedea184144e3feaf4e595d78345d9a9d8b43da87912efbe4df3c8a318383836040518084600160a060020a0316600160a060020a0316815260200183815260200180602001828103825283818151815260200191508051906020019080838360005b8381101561028e578181015183820152602001610276565b50505050905090810190601f1680156102bb5780820380516001836020036101000a031916815260200191505b5094505050505060405180910390a16102d4565b600080fd5b505050565b600054600160a060020a0316815600a165627a7a72305820e15ee5e2160fdc89ce720dc909c6dc0f003d58418735db64a66e99fd3338afa3002900000000000000000000000098d0c1a1045a3145ea8d06f1db575819c8a7c9bd",
-			"Text": "Data: 608060405234801561001057600080fd5b50604051602080610366833981016040525160008054600160a060020a03909216600160a060020a0319909216919091179055610314806100526000396000f30060806040526004361061004b5763ffffffff7c01000000000000000000000000000000000000000000000000000000006000350416633f579f42811461004d578063d2ec4a92146100b6575b005b34801561005957600080fd5b50604080516020600460443581810135601f810184900484028501840190955284845261004b948235600160a060020a03169460248035953695946064949201919081908401838280828437509497506100e79650505050505050565b3480156100c257600080fd5b506100cb6102d9565b60408051600160a060020a039092168252519081900360200190f35b6000809054906101000a9004600160a060020a0316600160a060020a031663c34c08e56040518163ffffffff167c0100000000000000000000000000000000000000000000000000000000028152600401602060405180830381600087803b15801561015257600080fd5b505af1158015610166573d6000803e3d6000fd5b505050506040513d602081101561017c57600080fd5b5051600160a060020a0316331461019257600080fd5b82600160a060020a0316828260405180828051906020019080838360005b838110156101c85781810151838201526020016101b0565b50505050905090810190601f1680156101f55780820380516001836020036101000a031916815260200191505b5091505060006040518083038185875af192505050156102cf577f39f46e1dedea184144e3feaf4e595d78345d9a9d8b43da87912efbe4df3c8a318383836040518084600160a060020a0316600160a060020a0316815260200183815260200180602001828103825283818151815260200191508051906020019080838360005b8381101561028e578181015183820152602001610276565b50505050905090810190601f1680156102bb5780820380516001836020036101000a031916815260200191505b5094505050505060405180910390a16102d4565b600080fd5b505050565b600054600160a060020a0316815600a165627a7a72305820e15ee5e2160fdc89ce720dc909c6dc0f003d58418735db64a66e99fd3338afa3002900000000000000000000000098d0c1a1045a3145ea8d06f1db575819c8a7c9bd",
-			"More": "Data being sent to a contract function. The first 4 bytes are known as the 'function selector'"
+			"Hex": "6040",
+			"Text": "PUSH1 0x40",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 23,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "52",
+			"Text": "MSTORE",
+			"More": "Stores a word to memory",
+			"Offset": 25,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "34",
+			"Text": "CALLVALUE",
+			"More": "Wei sent with the call",
+			"Offset": 26,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "80",
+			"Text": "DUP1",
+			"More": "Duplicates stack item 1",
+			"Offset": 27,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "15",
+			"Text": "ISZERO",
+			"More": "1 if the value is 0, 0 otherwise",
+			"Offset": 28,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "610010",
+			"Text": "PUSH2 0x0010",
+			"More": "Pushes the next 2 bytes of code",
+			"Offset": 29,
+			"Length": 3,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "57",
+			"Text": "JUMPI",
+			"More": "Jumps to a JUMPDEST if the condition is not 0",
+			"Offset": 32,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "6000",
+			"Text": "PUSH1 0x00",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 33,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "80",
+			"Text": "DUP1",
+			"More": "Duplicates stack item 1",
+			"Offset": 35,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "fd",
+			"Text": "REVERT",
+			"More": "Halts execution reverting state changes, returning a range of memory",
+			"Offset": 36,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "5b",
+			"Text": "JUMPDEST",
+			"More": "Marks a valid jump destination",
+			"Offset": 37,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "50",
+			"Text": "POP",
+			"More": "Removes the top of the stack",
+			"Offset": 38,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "6040",
+			"Text": "PUSH1 0x40",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 39,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "51",
+			"Text": "MLOAD",
+			"More": "Loads a word from memory",
+			"Offset": 41,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "6020",
+			"Text": "PUSH1 0x20",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 42,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "80",
+			"Text": "DUP1",
+			"More": "Duplicates stack item 1",
+			"Offset": 44,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "610366",
+			"Text": "PUSH2 0x0366",
+			"More": "Pushes the next 2 bytes of code",
+			"Offset": 45,
+			"Length": 3,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "83",
+			"Text": "DUP4",
+			"More": "Duplicates stack item 4",
+			"Offset": 48,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "39",
+			"Text": "CODECOPY",
+			"More": "Copies the executing code to memory. Takes the memory offset, the code offset and the size",
+			"Offset": 49,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "81",
+			"Text": "DUP2",
+			"More": "Duplicates stack item 2",
+			"Offset": 50,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "01",
+			"Text": "ADD",
+			"More": "Addition modulo 2^256",
+			"Offset": 51,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "6040",
+			"Text": "PUSH1 0x40",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 52,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "52",
+			"Text": "MSTORE",
+			"More": "Stores a word to memory",
+			"Offset": 54,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "51",
+			"Text": "MLOAD",
+			"More": "Loads a word from memory",
+			"Offset": 55,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "6000",
+			"Text": "PUSH1 0x00",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 56,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "80",
+			"Text": "DUP1",
+			"More": "Duplicates stack item 1",
+			"Offset": 58,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "54",
+			"Text": "SLOAD",
+			"More": "Loads a word from storage",
+			"Offset": 59,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "6001",
+			"Text": "PUSH1 0x01",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 60,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "60a0",
+			"Text": "PUSH1 0xa0",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 62,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "6002",
+			"Text": "PUSH1 0x02",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 64,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "0a",
+			"Text": "EXP",
+			"More": "Exponentiation modulo 2^256",
+			"Offset": 66,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "03",
+			"Text": "SUB",
+			"More": "Subtraction modulo 2^256",
+			"Offset": 67,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "90",
+			"Text": "SWAP1",
+			"More": "Swaps the top of the stack with stack item 2",
+			"Offset": 68,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "92",
+			"Text": "SWAP3",
+			"More": "Swaps the top of the stack with stack item 4",
+			"Offset": 69,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "16",
+			"Text": "AND",
+			"More": "Bitwise and",
+			"Offset": 70,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "6001",
+			"Text": "PUSH1 0x01",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 71,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "60a0",
+			"Text": "PUSH1 0xa0",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 73,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "6002",
+			"Text": "PUSH1 0x02",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 75,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "0a",
+			"Text": "EXP",
+			"More": "Exponentiation modulo 2^256",
+			"Offset": 77,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "03",
+			"Text": "SUB",
+			"More": "Subtraction modulo 2^256",
+			"Offset": 78,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "19",
+			"Text": "NOT",
+			"More": "Bitwise not",
+			"Offset": 79,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "90",
+			"Text": "SWAP1",
+			"More": "Swaps the top of the stack with stack item 2",
+			"Offset": 80,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "92",
+			"Text": "SWAP3",
+			"More": "Swaps the top of the stack with stack item 4",
+			"Offset": 81,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "16",
+			"Text": "AND",
+			"More": "Bitwise and",
+			"Offset": 82,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "91",
+			"Text": "SWAP2",
+			"More": "Swaps the top of the stack with stack item 3",
+			"Offset": 83,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "90",
+			"Text": "SWAP1",
+			"More": "Swaps the top of the stack with stack item 2",
+			"Offset": 84,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "91",
+			"Text": "SWAP2",
+			"More": "Swaps the top of the stack with stack item 3",
+			"Offset": 85,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "17",
+			"Text": "OR",
+			"More": "Bitwise or",
+			"Offset": 86,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "90",
+			"Text": "SWAP1",
+			"More": "Swaps the top of the stack with stack item 2",
+			"Offset": 87,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "55",
+			"Text": "SSTORE",
+			"More": "Stores a word to storage",
+			"Offset": 88,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "610314",
+			"Text": "PUSH2 0x0314",
+			"More": "Pushes the next 2 bytes of code",
+			"Offset": 89,
+			"Length": 3,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "80",
+			"Text": "DUP1",
+			"More": "Duplicates stack item 1",
+			"Offset": 92,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "610052",
+			"Text": "PUSH2 0x0052",
+			"More": "Pushes the next 2 bytes of code",
+			"Offset": 93,
+			"Length": 3,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "6000",
+			"Text": "PUSH1 0x00",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 96,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "39",
+			"Text": "CODECOPY: copies the 788 byte runtime code at offset 82 of the init code to memory",
+			"More": "The end of the constructor. Copies the executing code to memory. Takes the memory offset, the code offset and the size",
+			"Offset": 98,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "6000",
+			"Text": "PUSH1 0x00",
+			"More": "Pushes the next 1 bytes of code",
+			"Offset": 99,
+			"Length": 2,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "f3",
+			"Text": "RETURN: returns the runtime code, the code of the new contract",
+			"More": "Whatever the init code returns is stored as the code of the new contract. Halts execution returning a range of memory",
+			"Offset": 101,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "00",
+			"Text": "STOP",
+			"More": "Halts execution successfully",
+			"Offset": 102,
+			"Length": 1,
+			"Field": "input",
+			"Parent": 0
+		},
+		{
+			"Hex": "60806040526004361061004b5763ffffffff7c01000000000000000000000000000000000000000000000000000000006000350416633f579f42811461004d578063d2ec4a92146100b6575b005b34801561005957600080fd5b50604080516020600460443581810135601f810184900484028501840190955284845261004b948235600160a060020a03169460248035953695946064949201919081908401838280828437509497506100e79650505050505050565b3480156100c257600080fd5b506100cb6102d9565b60408051600160a060020a039092168252519081900360200190f35b6000809054906101000a9004600160a060020a0316600160a060020a031663c34c08e56040518163ffffffff167c0100000000000000000000000000000000000000000000000000000000028152600401602060405180830381600087803b15801561015257600080fd5b505af1158015610166573d6000803e3d6000fd5b505050506040513d602081101561017c57600080fd5b5051600160a060020a0316331461019257600080fd5b82600160a060020a0316828260405180828051906020019080838360005b838110156101c85781810151838201526020016101b0565b50505050905090810190601f1680156101f55780820380516001836020036101000a031916815260200191505b5091505060006040518083038185875af192505050156102cf577f39f46e1dedea184144e3feaf4e595d78345d9a9d8b43da87912efbe4df3c8a318383836040518084600160a060020a0316600160a060020a0316815260200183815260200180602001828103825283818151815260200191508051906020019080838360005b8381101561028e578181015183820152602001610276565b50505050905090810190601f1680156102bb5780820380516001836020036101000a031916815260200191505b5094505050505060405180910390a16102d4565b600080fd5b505050565b600054600160a060020a0316815600a165627a7a72305820e15ee5e2160fdc89ce720dc909c6dc0f003d58418735db64a66e99fd3338afa30029",
+			"Text": "Runtime Code: 788 bytes, metadata bzz-raw://e15ee5e2160fdc89ce720dc909c6dc0f003d58418735db64a66e99fd3338afa3",
+			"More": "The code of the new contract. The constructor copies it to memory and returns it, it doesn't run while deploying",
+			"Offset": 103,
+			"Length": 788,
+			"Field": "input",
+			"Parent": 0,
+			"Children": [
+				{
+					"Hex": "a1",
+					"Text": "Metadata: a CBOR map of 1 entries",
+					"More": "Solidity appends metadata to the runtime code, CBOR encoded. It is never executed, the code in front of it always ends before reaching it",
+					"Offset": 848,
+					"Length": 1,
+					"Field": "input",
+					"Parent": -1,
+					"Depth": 1
+				},
+				{
+					"Hex": "65627a7a72305820e15ee5e2160fdc89ce720dc909c6dc0f003d58418735db64a66e99fd3338afa3",
+					"Text": "Metadata Hash: bzz-raw://e15ee5e2160fdc89ce720dc909c6dc0f003d58418735db64a66e99fd3338afa3",
+					"More": "The Swarm hash of the metadata JSON (bzzr0, used by older compilers), which holds the compiler settings and the hashes of the source files",
+					"Offset": 849,
+					"Length": 40,
+					"Field": "input",
+					"Parent": -1,
+					"Depth": 1
+				},
+				{
+					"Hex": "0029",
+					"Text": "Metadata Length: 41 bytes",
+					"More": "The length of the CBOR metadata in front of it, as 2 big endian bytes. Tools find the metadata by reading the last 2 bytes of the code",
+					"Offset": 889,
+					"Length": 2,
+					"Field": "input",
+					"Parent": -1,
+					"Depth": 1
+				}
+			]
+		},
+		{
+			"Hex": "00000000000000000000000098d0c1a1045a3145ea8d06f1db575819c8a7c9bd",
+			"Text": "Constructor Argument 0: 0x98d0C1a1045a3145eA8d06F1db575819C8A7c9Bd",
+			"More": "The ABI encoded constructor arguments, appended to the init code by the deployer. Upload the ABI with the request to decode them",
+			"Offset": 891,
+			"Length": 32,
+			"Field": "input",
+			"Parent": 0
 		},
 		{
 			"Hex": "25",
-			"Text": "Signature Prefix Value (v): 25",
-			"More": "Indicates both the chainID of the transaction and the parity (odd or even) of the y component of the public key"
+			"Text": "Signature Prefix Value (v): 37 = chain ID 1 (Mainnet), y parity 0",
+			"More": "Indicates both the chainID of the transaction and the parity (odd or even) of the y component of the public key. EIP-155 encodes v = chainId * 2 + 35 + parity = 1 * 2 + 35 + 0, which ties the signature to one chain and protects it from replay on other chains",
+			"Offset": 923,
+			"Length": 1,
+			"Field": "v",
+			"Parent": 0
 		},
 		{
 			"Hex": "a078a6f18a1036ed7e23dd63481fd1cd62064e8cd4b03ee8b0a377c190cb9113a8",
 			"Text": "Signature (r) value: 78a6f18a1036ed7e23dd63481fd1cd62064e8cd4b03ee8b0a377c190cb9113a8",
-			"More": "Part of the signature pair (r,s). Represents the X-coordinate of an ephemeral public key created during the ECDSA signing process"
+			"More": "Part of the signature pair (r,s). Represents the X-coordinate of an ephemeral public key created during the ECDSA signing process",
+			"Offset": 924,
+			"Length": 33,
+			"Field": "r",
+			"Parent": 0
 		},
 		{
 			"Hex": "a0197177c1425a243e39d487c0ac40faa595b03f0dff537555df4256b8d09e7989",
 			"Text": "Signature (s) value: 197177c1425a243e39d487c0ac40faa595b03f0dff537555df4256b8d09e7989",
-			"More": "Part of the signature pair (r,s). Generated using the ECDSA signing algorithm"
+			"More": "Part of the signature pair (r,s). Generated using the ECDSA signing algorithm",
+			"Offset": 957,
+			"Length": 33,
+			"Field": "s",
+			"Parent": 0
+		},
+		{
+			"Hex": "",
+			"Text": "Intrinsic Gas: 66230",
+			"More": "Derived, not part of the raw transaction. The gas charged before any code runs: 21000 base + 32000 for creating a contract + 2 × 29 words of init code + 16 × 797 non-zero data bytes + 4 × 105 zero data bytes",
+			"Offset": 990,
+			"Length": 0,
+			"Field": "intrinsicGas",
+			"Parent": -1
+		},
+		{
+			"Hex": "",
+			"Text": "Max Fee: 0.00285843 ETH (2858430000000000 wei)",
+			"More": "Derived, not part of the raw transaction. The most the transaction can pay for gas, the Gas Limit 285843 × the Gas Price 10 gwei (10000000000 wei)",
+			"Offset": 990,
+			"Length": 0,
+			"Field": "maxFee",
+			"Parent": -1
+		},
+		{
+			"Hex": "",
+			"Text": "Max Total Cost: 0.00285843 ETH (2858430000000000 wei)",
+			"More": "Derived, not part of the raw transaction. The maximum fee plus the value sent. Nodes only accept the transaction if the sender's balance covers all of it",
+			"Offset": 990,
+			"Length": 0,
+			"Field": "maxCost",
+			"Parent": -1
+		},
+		{
+			"Hex": "",
+			"Text": "Transaction Hash: 0x8b9ba18afff81fef39d05f8f403b64b9b60068e39701c3ab6beb124705a7a563",
+			"More": "Derived, not part of the raw transaction. The keccak256 hash of the signed transaction (including the type byte of typed transactions). This is the ID used to look the transaction up in a block explorer",
+			"Offset": 990,
+			"Length": 0,
+			"Field": "hash",
+			"Parent": -1
+		},
+		{
+			"Hex": "",
+			"Text": "Signing Preimage: 0xf9039b82265a8502540be40083045c938080b90386608060405234801561001057600080fd5b50604051602080610366833981016040525160008054600160a060020a03909216600160a060020a0319909216919091179055610314806100526000396000f30060806040526004361061004b5763ffffffff7c01000000000000000000000000000000000000000000000000000000006000350416633f579f42811461004d578063d2ec4a92146100b6575b005b34801561005957600080fd5b50604080516020600460443581810135601f810184900484028501840190955284845261004b948235600160a060020a03169460248035953695946064949201919081908401838280828437509497506100e79650505050505050565b3480156100c257600080fd5b506100cb6102d9565b60408051600160a060020a039092168252519081900360200190f35b6000809054906101000a9004600160a060020a0316600160a060020a031663c34c08e56040518163ffffffff167c0100000000000000000000000000000000000000000000000000000000028152600401602060405180830381600087803b15801561015257600080fd5b505af1158015610166573d6000803e3d6000fd5b505050506040513d602081101561017c57600080fd5b5051600160a060020a0316331461019257600080fd5b82600160a060020a0316828260405180828051906020019080838360005b838110156101c85781810151838201526020016101b0565b50505050905090810190601f1680156101f55780820380516001836020036101000a031916815260200191505b5091505060006040518083038185875af192505050156102cf577f39f46e1dedea184144e3feaf4e595d78345d9a9d8b43da87912efbe4df3c8a318383836040518084600160a060020a0316600160a060020a0316815260200183815260200180602001828103825283818151815260200191508051906020019080838360005b8381101561028e578181015183820152602001610276565b50505050905090810190601f1680156102bb5780820380516001836020036101000a031916815260200191505b5094505050505060405180910390a16102d4565b600080fd5b505050565b600054600160a060020a0316815600a165627a7a72305820e15ee5e2160fdc89ce720dc909c6dc0f003d58418735db64a66e99fd3338afa3002900000000000000000000000098d0c1a1045a3145ea8d06f1db575819c8a7c9bd018080",
+			"More": "Derived, not part of the raw transaction. The exact bytes the sender signed using the EIP-155 (chain 1) scheme. It is the RLP list [nonce, gasPrice, gasLimit, to, value, data, chainId, 0, 0]. EIP-155 replaces v, r and s with the chain ID and two zeros, so the signature is only valid on this chain",
+			"Offset": 990,
+			"Length": 0,
+			"Field": "signingPreimage",
+			"Parent": -1
+		},
+		{
+			"Hex": "",
+			"Text": "Signing Hash: 0x98f1b2f5d5a760c51e5384b1185a6dd3a5de8b2a8086ecf65ff2360b7e4496ac",
+			"More": "Derived, not part of the raw transaction. The keccak256 hash of the signing preimage. This 32 byte hash is what the ECDSA signature (v, r, s) actually signs",
+			"Offset": 990,
+			"Length": 0,
+			"Field": "signingHash",
+			"Parent": -1
+		},
+		{
+			"Hex": "",
+			"Text": "Sender: 0xA9A8bDaEfB34eA98bE19baDB76bB686136412473",
+			"More": "Derived, not part of the raw transaction. Recovered from the signature (r, s and the y parity) and the EIP-155 (chain 1) signing hash. Public key: 0x04a3cff6499b048f0eb4c2072f893b8f7e985d646ee27e9574d68c95a740f3aa27d34d5f5ac41acd40a5f4c190f12511f223c0b59cee756d83cf7738070794696b",
+			"Offset": 990,
+			"Length": 0,
+			"Field": "sender",
+			"Parent": -1
 		}
 	]
 }`
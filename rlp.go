@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/labstack/echo"
+)
+
+func rlpHandler(c echo.Context) error {
+	str := strings.TrimPrefix(c.Param("rlp"), "0x")
+	buf, err := hex.DecodeString(str)
+	if err != nil {
+		out, _ := json.MarshalIndent(Splain{Error: hexError(str, err)}, "", "	")
+		return c.JSONBlob(http.StatusBadRequest, out)
+	}
+
+	out, err := rlpExplain(buf)
+	if err != nil {
+		return c.JSONBlob(http.StatusBadRequest, out)
+	}
+	return c.String(http.StatusOK, string(out))
+}
+
+// rlpExplain breaks down any rlp encoded data without knowing its schema (receipts, trie nodes,
+// devp2p messages...). Every list prefix token holds the tokens of the list items as its Children,
+// so unlike transaction tokens the Parent of every token is -1
+func rlpExplain(buf []byte) ([]byte, error) {
+	splain := Splain{}
+	splain.Tokens, splain.Error = rlpTokens(buf, 0, 0)
+
+	out, _ := json.MarshalIndent(splain, "", "	")
+	if splain.Error != nil {
+		return out, splain.Error
+	}
+	return out, nil
+}
+
+// rlpTokens tokenizes the sequence of rlp items in buf, which starts at offset off of the input.
+// On a malformed item the tokens of the items before it are returned with the error
+func rlpTokens(buf []byte, off, depth int) ([]Token, *ParseError) {
+	var tokens []Token
+	for len(buf) > 0 {
+		it, rest, err := splitRLP(buf)
+		if err != nil {
+			return tokens, &ParseError{off, err.Error()}
+		}
+
+		// borrow the prefix explanations used for transaction fields
+		var prefix Splain
+		i := addRLPNode(&prefix, it.enc)
+
+		switch {
+		case it.kind == rlp.List:
+			tok := prefix.Tokens[0]
+			tok.Depth = depth
+			tok.Offset = off
+			prefix.warnNonCanonical(&tok, off, it.noncanon)
+			children, perr := rlpTokens(it.content, off+i, depth+1)
+			tok.Children = children
+			tokens = append(tokens, tok)
+			if perr != nil {
+				return tokens, perr
+			}
+		case i == 0:
+			tokens = append(tokens, Token{
+				Hex:    Hex(it.enc),
+				Text:   fmt.Sprintf("Byte: 0x%02x (%d)", it.enc[0], it.enc[0]),
+				More:   "A single byte below 0x80 is its own RLP encoding, it needs no prefix",
+				Offset: off,
+				Length: 1,
+				Parent: -1,
+				Depth:  depth,
+			})
+		default:
+			tok := prefix.Tokens[0]
+			tok.Depth = depth
+			tok.Offset = off
+			prefix.warnNonCanonical(&tok, off, it.noncanon)
+			tokens = append(tokens, tok)
+			txt, more := rlpStringInfo(it.content)
+			tokens = append(tokens, Token{Hex: Hex(it.content), Text: txt, More: more, Offset: off + i, Length: len(it.content), Parent: -1, Depth: depth})
+		}
+		off += len(it.enc)
+		buf = rest
+	}
+	return tokens, nil
+}
+
+// rlpItem is a single rlp item read from the raw input
+type rlpItem struct {
+	kind     rlp.Kind
+	enc      []byte // the whole item, prefix included
+	content  []byte // the payload after the prefix
+	noncanon string // why the encoding is not canonical, empty if it is
+}
+
+// splitRLP reads the first rlp item in b and returns it with the bytes after it. Unlike rlp.Split it
+// accepts non-canonical encodings so they can be shown and explained, and only fails when an item
+// doesn't fit in b
+func splitRLP(b []byte) (rlpItem, []byte, error) {
+	var it rlpItem
+	if len(b) == 0 {
+		return it, nil, fmt.Errorf("expected an RLP item but the input ended")
+	}
+
+	prefix := b[0]
+	hdr, size := 1, uint64(0)
+	var err error
+	switch {
+	case prefix < 0x80:
+		it.kind = rlp.Byte
+		hdr, size = 0, 1
+	case prefix < 0xB8:
+		it.kind = rlp.String
+		size = uint64(prefix - 0x80)
+		if size == 1 && len(b) > 1 && b[1] < 0x80 {
+			it.noncanon = fmt.Sprintf("the single byte 0x%02x is below 0x80 so it must be encoded as itself, without the 0x81 prefix", b[1])
+		}
+	case prefix < 0xC0:
+		it.kind = rlp.String
+		hdr, size, it.noncanon, err = longSize(b, prefix-0xb7, 0x80)
+	case prefix < 0xF8:
+		it.kind = rlp.List
+		size = uint64(prefix - 0xc0)
+	default:
+		it.kind = rlp.List
+		hdr, size, it.noncanon, err = longSize(b, prefix-0xf7, 0xc0)
+	}
+	if err != nil {
+		return it, nil, err
+	}
+	if size > uint64(len(b)-hdr) {
+		return it, nil, fmt.Errorf("the RLP prefix 0x%x promises %d bytes but only %d are left", b[:hdr], size, len(b)-hdr)
+	}
+
+	end := hdr + int(size)
+	it.enc = b[:end]
+	it.content = b[hdr:end]
+	return it, b[end:], nil
+}
+
+// longSize reads the n byte big endian length that follows a long form prefix.
+// short is the first short form prefix of the same kind, 0x80 for strings or 0xc0 for lists
+func longSize(b []byte, n byte, short byte) (int, uint64, string, error) {
+	if len(b) < 1+int(n) {
+		return 0, 0, "", fmt.Errorf("the %d byte length after the RLP prefix 0x%02x is cut off", n, b[0])
+	}
+	var size uint64
+	for _, c := range b[1 : 1+n] {
+		size = size<<8 | uint64(c)
+	}
+
+	noncanon := ""
+	if b[1] == 0 {
+		noncanon = fmt.Sprintf("the length 0x%x has leading zero bytes", b[1:1+n])
+	} else if size < 56 {
+		noncanon = fmt.Sprintf("a payload of %d bytes must use the short form prefix 0x%02x, the long form is only for payloads of more than 55 bytes", size, short+byte(size))
+	}
+	return 1 + int(n), size, noncanon, nil
+}
+
+// countRLP counts the items in the payload of an rlp list, up to the first one that is malformed
+func countRLP(b []byte) int {
+	n := 0
+	for len(b) > 0 {
+		_, rest, err := splitRLP(b)
+		if err != nil {
+			break
+		}
+		b = rest
+		n++
+	}
+	return n
+}
+
+// intNonCanonical explains why the rlp string of an integer is not canonical, if it isn't
+func intNonCanonical(it rlpItem) string {
+	if len(it.content) == 0 || it.content[0] != 0 {
+		return ""
+	}
+	if len(it.content) == 1 {
+		return "the integer 0 must be encoded as the empty string 0x80, not as the byte 0x00"
+	}
+	trimmed := new(big.Int).SetBytes(it.content).Bytes()
+	return fmt.Sprintf("integers must not have leading zero bytes, 0x%x should be 0x%x", it.content, trimmed)
+}
+
+// joinReasons combines the explanations of several non-canonical encodings of one item
+func joinReasons(reasons ...string) string {
+	var out []string
+	for _, r := range reasons {
+		if r != "" {
+			out = append(out, r)
+		}
+	}
+	return strings.Join(out, " and ")
+}
+
+// rlpStringInfo guesses what an rlp string holds from its length and contents
+func rlpStringInfo(b []byte) (string, string) {
+	txt := fmt.Sprintf("String (%d bytes): 0x%x", len(b), b)
+	switch {
+	case len(b) == 0:
+		return "Empty String", "The empty string, which is also how RLP encodes the integer 0 and an empty byte array"
+	case isPrintable(b):
+		return fmt.Sprintf("String (%d bytes): %q", len(b), b), "A byte string that happens to be printable text"
+	case len(b) == 20:
+		return txt, "20 bytes, most likely an address"
+	case len(b) == 32:
+		return txt, "32 bytes, most likely a hash or a 256 bit integer"
+	case len(b) <= 8 && b[0] != 0:
+		var i uint64
+		for _, c := range b {
+			i = i<<8 | uint64(c)
+		}
+		return txt, fmt.Sprintf("Read as a big endian integer this is %d. RLP has no integer type, integers are byte strings without leading zeros", i)
+	}
+	return txt, "A byte string. RLP does not say what it means, that is up to the schema of whatever was encoded"
+}
+
+func isPrintable(b []byte) bool {
+	for _, c := range b {
+		if c > unicode.MaxASCII || !unicode.IsPrint(rune(c)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+//go:embed data/selectors.txt
+var embeddedSelectors string
+
+// selectors maps function selectors to the text signatures of every function known to have them
+var selectors = map[[4]byte][]string{}
+
+func init() {
+	if err := loadSelectors(strings.NewReader(embeddedSelectors)); err != nil {
+		panic(err)
+	}
+}
+
+// loadSelectorFile adds the selectors in the file at path to the database
+func loadSelectorFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return loadSelectors(f)
+}
+
+// loadSelectors reads "0xselector signature" lines into the database. Blank lines and lines
+// starting with # are skipped, a selector that isn't the hash of its signature is an error
+func loadSelectors(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return fmt.Errorf("line %d: want a selector and a signature, got %q", n, line)
+		}
+		var sel [4]byte
+		copy(sel[:], crypto.Keccak256([]byte(fields[1])))
+		if !strings.EqualFold(fields[0], fmt.Sprintf("0x%x", sel)) {
+			return fmt.Errorf("line %d: the selector of %s is 0x%x, not %s", n, fields[1], sel, fields[0])
+		}
+		if _, err := parseSignature(fields[1]); err != nil {
+			return fmt.Errorf("line %d: %s: %v", n, fields[1], err)
+		}
+		known := false
+		for _, sig := range selectors[sel] {
+			known = known || sig == fields[1]
+		}
+		if !known {
+			selectors[sel] = append(selectors[sel], fields[1])
+		}
+	}
+	return scanner.Err()
+}
+
+// selectorMethods returns a method for every known signature with the selector at the start of data
+func selectorMethods(data []byte) []abi.Method {
+	var methods []abi.Method
+	for _, sig := range selectors[[4]byte(data[:4])] {
+		// every signature parsed when it was loaded
+		method, _ := parseSignature(sig)
+		methods = append(methods, method)
+	}
+	return methods
+}
+
+// parseSignature turns a text signature like transfer(address,uint256) into a method whose
+// arguments have no names
+func parseSignature(sig string) (abi.Method, error) {
+	open := strings.Index(sig, "(")
+	if open < 1 || !strings.HasSuffix(sig, ")") {
+		return abi.Method{}, fmt.Errorf("not a function signature")
+	}
+	var inputs abi.Arguments
+	for _, s := range splitTypes(sig[open+1 : len(sig)-1]) {
+		m, err := typeMarshaling(s)
+		if err != nil {
+			return abi.Method{}, err
+		}
+		t, err := abi.NewType(m.Type, "", m.Components)
+		if err != nil {
+			return abi.Method{}, err
+		}
+		clearTupleNames(&t)
+		inputs = append(inputs, abi.Argument{Type: t})
+	}
+	name := sig[:open]
+	return abi.NewMethod(name, name, abi.Function, "", false, false, inputs, nil), nil
+}
+
+// splitTypes splits a comma separated list of types, leaving the commas inside tuples alone
+func splitTypes(list string) []string {
+	if list == "" {
+		return nil
+	}
+	var types []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				types = append(types, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(types, list[start:])
+}
+
+// typeMarshaling describes a type like (address,uint256)[] the way abi.NewType takes it
+func typeMarshaling(t string) (abi.ArgumentMarshaling, error) {
+	if !strings.HasPrefix(t, "(") {
+		return abi.ArgumentMarshaling{Type: t}, nil
+	}
+	end := strings.LastIndex(t, ")")
+	if end < 0 {
+		return abi.ArgumentMarshaling{}, fmt.Errorf("unbalanced tuple %s", t)
+	}
+	// abi.NewType needs valid names for the tuple components, they are cleared afterwards
+	m := abi.ArgumentMarshaling{Type: "tuple" + t[end+1:]}
+	for i, s := range splitTypes(t[1:end]) {
+		c, err := typeMarshaling(s)
+		if err != nil {
+			return m, err
+		}
+		c.Name = fmt.Sprintf("c%d", i)
+		m.Components = append(m.Components, c)
+	}
+	return m, nil
+}
+
+// clearTupleNames drops the made up component names of tuples in t
+func clearTupleNames(t *abi.Type) {
+	if t.Elem != nil {
+		clearTupleNames(t.Elem)
+	}
+	for i, e := range t.TupleElems {
+		t.TupleRawNames[i] = ""
+		clearTupleNames(e)
+	}
+}
+
+// selectorNames lists the known signatures for the selector at the start of data
+func selectorNames(data []byte) []string {
+	if len(data) < 4 {
+		return nil
+	}
+	return selectors[[4]byte(data[:4])]
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// txSigner returns the signer that produced the signature of tx and a name for it. Typed transactions
+// with chain ID 0 have no signer, go-ethereum refuses to build one
+func txSigner(tx *types.Transaction) (types.Signer, string, error) {
+	switch {
+	case tx.Type() != types.LegacyTxType:
+		name := fmt.Sprintf("%s (chain %s)", txTypeNames[tx.Type()], tx.ChainId())
+		if tx.ChainId().Sign() == 0 {
+			return nil, name, fmt.Errorf("typed transactions can't have chain ID 0")
+		}
+		return types.LatestSignerForChainID(tx.ChainId()), name, nil
+	case tx.Protected():
+		return types.NewEIP155Signer(tx.ChainId()), fmt.Sprintf("EIP-155 (chain %s)", tx.ChainId()), nil
+	}
+	return types.HomesteadSigner{}, "Homestead", nil
+}
+
+// recoverSender recovers the uncompressed public key and address of the account that signed tx
+func recoverSender(tx *types.Transaction) (common.Address, []byte, error) {
+	signer, _, err := txSigner(tx)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	v, r, s := tx.RawSignatureValues()
+
+	// the recovery id is the y parity, which legacy transactions offset by 27 or by chainId*2+35
+	recid := new(big.Int).Set(v)
+	if tx.Type() == types.LegacyTxType {
+		if tx.Protected() {
+			recid.Sub(recid, new(big.Int).Add(new(big.Int).Mul(tx.ChainId(), big.NewInt(2)), big.NewInt(35)))
+		} else {
+			recid.Sub(recid, big.NewInt(27))
+		}
+	}
+	if !recid.IsUint64() || recid.Uint64() > 1 {
+		return common.Address{}, nil, fmt.Errorf("invalid signature recovery id %s", recid)
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[64] = byte(recid.Uint64())
+
+	pub, err := crypto.Ecrecover(signer.Hash(tx).Bytes(), sig)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	var addr common.Address
+	copy(addr[:], crypto.Keccak256(pub[1:])[12:])
+	return addr, pub, nil
+}
+
+// warnSignature puts a warning in front of the explanation of the v or y parity token, the value
+// that picks which public key the signature recovers to
+func (s *Splain) warnSignature(warning string) {
+	for i := len(s.Tokens) - 1; i >= 0; i-- {
+		if f := s.Tokens[i].Field; f == fieldKeys[SIG_V] || f == fieldKeys[SIG_Y_PARITY] {
+			s.Tokens[i].More = warning + s.Tokens[i].More
+			return
+		}
+	}
+}
+
+// add a derived node (not part of the raw bytes) for the address that sent tx
+func (s *Splain) addSenderNode(tx *types.Transaction, verbose bool) {
+	var tok Token
+	tok.Field = "sender"
+	addr, pub, err := recoverSender(tx)
+	if err != nil {
+		tok.Text = "Sender: unknown"
+		tok.More = fmt.Sprintf("WARNING: the sender could not be recovered from the signature (%v). Nodes reject this transaction", err)
+		s.addToken(tok, 0)
+		s.warnSignature(fmt.Sprintf("WARNING: no public key can be recovered from this signature (%v), so the transaction has no sender and nodes reject it. ", err))
+		return
+	}
+	_, name, _ := txSigner(tx)
+
+	tok.Text = fmt.Sprintf("Sender: %s", addr.Hex())
+	tok.More = fmt.Sprintf("Derived, not part of the raw transaction. Recovered from the signature (r, s and the y parity) and the %s signing hash. Public key: 0x%x", name, pub)
+	if _, _, sig := tx.RawSignatureValues(); sig.Cmp(secp256k1halfN) > 0 {
+		tok.Text += " (REJECTED high-s signature)"
+		tok.More = "WARNING: the signature has a high s value, which nodes reject since Homestead (EIP-2). This address would be the sender, but the transaction is never accepted as it is. " + tok.More
+	}
+	if verbose {
+		tok.More += ". The sender is never sent over the wire: ECDSA public key recovery turns the signature and the hash that was signed back into the uncompressed public key (0x04 followed by the x and y coordinates), and the address is the last 20 bytes of the Keccak-256 hash of the 64 coordinate bytes"
+	}
+	s.addToken(tok, 0)
+}
+
+// signingPreimage rebuilds the exact bytes whose keccak256 hash the sender signed.
+// The signature fields are dropped and legacy EIP-155 transactions put [chainId, 0, 0] in their place
+func signingPreimage(tx *types.Transaction) []byte {
+	var fields []interface{}
+	for _, f := range txLayouts[tx.Type()] {
+		switch f {
+		case SIG_V, SIG_Y_PARITY, SIG_R, SIG_S:
+			continue
+		}
+		fields = append(fields, fieldValue(tx, f))
+	}
+	if tx.Type() == types.LegacyTxType && tx.Protected() {
+		fields = append(fields, tx.ChainId(), uint(0), uint(0))
+	}
+	enc, _ := rlp.EncodeToBytes(fields)
+	if tx.Type() == types.LegacyTxType {
+		return enc
+	}
+	return append([]byte{tx.Type()}, enc...)
+}
+
+// add derived nodes for the transaction hash and for the hash and preimage the sender signed
+func (s *Splain) addHashNodes(tx *types.Transaction, verbose bool) {
+	var tok Token
+	tok.Field = "hash"
+	tok.Text = fmt.Sprintf("Transaction Hash: 0x%x", tx.Hash())
+	tok.More = "Derived, not part of the raw transaction. The keccak256 hash of the signed transaction (including the type byte of typed transactions). This is the ID used to look the transaction up in a block explorer"
+	if tx.Type() == types.BlobTxType {
+		tok.More += ". For blob transactions it is the hash of the canonical form, without blobs, commitments and proofs"
+	}
+	s.addToken(tok, 0)
+
+	signer, name, err := txSigner(tx)
+	preimage := signingPreimage(tx)
+	tok = Token{Field: "signingPreimage"}
+	tok.Text = fmt.Sprintf("Signing Preimage: 0x%x", preimage)
+	tok.More = fmt.Sprintf("Derived, not part of the raw transaction. The exact bytes the sender signed using the %s scheme. %s", name, preimageInfo(tx))
+	s.addToken(tok, 0)
+
+	tok = Token{Field: "signingHash"}
+	if err != nil {
+		tok.Text = "Signing Hash: unknown"
+		tok.More = fmt.Sprintf("WARNING: there is no signing hash, %v. No signature can be valid for this transaction and nodes reject it", err)
+		s.addToken(tok, 0)
+		return
+	}
+	hash := signer.Hash(tx)
+	tok.Text = fmt.Sprintf("Signing Hash: 0x%x", hash)
+	tok.More = "Derived, not part of the raw transaction. The keccak256 hash of the signing preimage. This 32 byte hash is what the ECDSA signature (v, r, s) actually signs"
+	if verbose {
+		tok.More += ". It differs from the transaction hash because the transaction hash also covers the signature"
+	}
+	if common.BytesToHash(crypto.Keccak256(preimage)) != hash {
+		tok.More = "WARNING: the signing preimage shown does not hash to the signing hash. " + tok.More
+	}
+	s.addToken(tok, 0)
+}
+
+func preimageInfo(tx *types.Transaction) string {
+	switch {
+	case tx.Type() != types.LegacyTxType:
+		return fmt.Sprintf("It is the type byte 0x%02x followed by the RLP list of every field except the signature (y parity, r, s). The chain ID is already one of the fields", tx.Type())
+	case tx.Protected():
+		return "It is the RLP list [nonce, gasPrice, gasLimit, to, value, data, chainId, 0, 0]. EIP-155 replaces v, r and s with the chain ID and two zeros, so the signature is only valid on this chain"
+	}
+	return "It is the RLP list [nonce, gasPrice, gasLimit, to, value, data]. The signature fields are left out and nothing ties the signature to a chain"
+}
+
+// order of the secp256k1 curve and half of it, the largest s allowed since Homestead
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1halfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// sigRangeWarning checks that a signature value is in the range [1, n-1] of the secp256k1 curve order
+func sigRangeWarning(name string, val *big.Int) string {
+	if val.Sign() == 0 {
+		return fmt.Sprintf("WARNING: %s is zero, which is never a valid ECDSA signature value. ", name)
+	}
+	if val.Cmp(secp256k1N) >= 0 {
+		return fmt.Sprintf("WARNING: %s is not below the secp256k1 curve order n, so this signature is invalid. ", name)
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// maxUint256 is the allowance wallets ask for when they want to be approved once and for all
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// tokenCalls explain the standard ERC-20, ERC-721 and ERC-1155 functions, keyed by signature.
+// They get the decoded arguments and the token contract, the recipient of the transaction
+var tokenCalls = map[string]func(args []interface{}, token common.Address) (string, string){
+	"transfer(address,uint256)":                                        erc20TransferInfo,
+	"approve(address,uint256)":                                         approveInfo,
+	"transferFrom(address,address,uint256)":                            transferFromInfo,
+	"safeTransferFrom(address,address,uint256)":                        safeTransferFromInfo,
+	"safeTransferFrom(address,address,uint256,bytes)":                  safeTransferFromInfo,
+	"setApprovalForAll(address,bool)":                                  setApprovalForAllInfo,
+	"safeTransferFrom(address,address,uint256,uint256,bytes)":          erc1155TransferInfo,
+	"safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)": erc1155BatchTransferInfo,
+}
+
+// tokenCallInfo describes a call to a standard token function in plain words. txt is empty
+// if method isn't one
+func tokenCallInfo(method *abi.Method, args []byte, to []byte) (string, string) {
+	info, ok := tokenCalls[method.Sig]
+	if !ok || len(to) != common.AddressLength {
+		return "", ""
+	}
+	vals, err := method.Inputs.Unpack(args)
+	if err != nil {
+		return "", ""
+	}
+	return info(vals, common.BytesToAddress(to))
+}
+
+func erc20TransferInfo(args []interface{}, token common.Address) (string, string) {
+	to, amount := args[0].(common.Address), args[1].(*big.Int)
+	txt := fmt.Sprintf("Transfer %s tokens of %s to %s", amount, token.Hex(), to.Hex())
+	return txt, shortERC20Amount
+}
+
+func approveInfo(args []interface{}, token common.Address) (string, string) {
+	spender, amount := args[0].(common.Address), args[1].(*big.Int)
+	var txt string
+	switch {
+	case amount.Cmp(maxUint256) == 0:
+		txt = fmt.Sprintf("Approve %s to spend UNLIMITED tokens of %s", spender.Hex(), token.Hex())
+	case amount.Sign() == 0:
+		txt = fmt.Sprintf("Revoke the approval of %s to spend tokens of %s", spender.Hex(), token.Hex())
+	default:
+		txt = fmt.Sprintf("Approve %s to spend %s tokens of %s", spender.Hex(), amount, token.Hex())
+	}
+	more := fmt.Sprintf("%s. If %s is an ERC-721 contract instead, this approves %s to transfer the NFT with token ID %s", shortApprove, token.Hex(), spender.Hex(), amount)
+	if amount.Cmp(maxUint256) == 0 {
+		more = "WARNING: the spender can move every token of this kind the sender has, now and in the future, until the approval is revoked. " + more
+	}
+	return txt, more
+}
+
+func transferFromInfo(args []interface{}, token common.Address) (string, string) {
+	from, to, amount := args[0].(common.Address), args[1].(common.Address), args[2].(*big.Int)
+	txt := fmt.Sprintf("Transfer %s tokens of %s from %s to %s", amount, token.Hex(), from.Hex(), to.Hex())
+	more := fmt.Sprintf("%s. If %s is an ERC-721 contract instead, this transfers the NFT with token ID %s", shortTransferFrom, token.Hex(), amount)
+	return txt, more
+}
+
+func safeTransferFromInfo(args []interface{}, token common.Address) (string, string) {
+	from, to, id := args[0].(common.Address), args[1].(common.Address), args[2].(*big.Int)
+	txt := fmt.Sprintf("Transfer NFT #%s of %s from %s to %s", id, token.Hex(), from.Hex(), to.Hex())
+	return txt, shortSafeTransferFrom
+}
+
+func setApprovalForAllInfo(args []interface{}, token common.Address) (string, string) {
+	operator, approved := args[0].(common.Address), args[1].(bool)
+	if !approved {
+		return fmt.Sprintf("Revoke the approval of %s to transfer all tokens of %s", operator.Hex(), token.Hex()), shortApprovalForAll
+	}
+	txt := fmt.Sprintf("Approve %s to transfer ALL tokens of %s", operator.Hex(), token.Hex())
+	return txt, "WARNING: the operator can move every NFT of this collection the sender has, now and in the future, until the approval is revoked. " + shortApprovalForAll
+}
+
+func erc1155TransferInfo(args []interface{}, token common.Address) (string, string) {
+	from, to, id, amount := args[0].(common.Address), args[1].(common.Address), args[2].(*big.Int), args[3].(*big.Int)
+	txt := fmt.Sprintf("Transfer %s of token ID %s of %s from %s to %s", amount, id, token.Hex(), from.Hex(), to.Hex())
+	return txt, shortERC1155Transfer
+}
+
+func erc1155BatchTransferInfo(args []interface{}, token common.Address) (string, string) {
+	from, to, ids, amounts := args[0].(common.Address), args[1].(common.Address), args[2].([]*big.Int), args[3].([]*big.Int)
+	if len(ids) != len(amounts) {
+		return fmt.Sprintf("Transfer tokens of %s from %s to %s", token.Hex(), from.Hex(), to.Hex()),
+			fmt.Sprintf("WARNING: %d token IDs but %d amounts, ERC-1155 contracts revert this call. %s", len(ids), len(amounts), shortERC1155Transfer)
+	}
+	parts := make([]string, len(ids))
+	for i := range ids {
+		parts[i] = fmt.Sprintf("%s of token ID %s", amounts[i], ids[i])
+	}
+	txt := fmt.Sprintf("Transfer %s of %s from %s to %s", strings.Join(parts, ", "), token.Hex(), from.Hex(), to.Hex())
+	return txt, shortERC1155Transfer
+}
+
+var shortERC20Amount = "An ERC-20 token transfer. Amounts are in the token's smallest unit, divide by 10^decimals of the token for whole tokens"
+var shortApprove = "An ERC-20 approval lets the spender move up to this amount of the sender's tokens with transferFrom. Amounts are in the token's smallest unit"
+var shortTransferFrom = "Moves tokens the sender was approved to spend. Amounts are in the token's smallest unit"
+var shortSafeTransferFrom = "An ERC-721 NFT transfer. The safe variant calls onERC721Received when the recipient is a contract, so NFTs can't get stuck in contracts that can't handle them"
+var shortApprovalForAll = "ERC-721 and ERC-1155 approval of an operator, usually a marketplace, for every token of the collection"
+var shortERC1155Transfer = "An ERC-1155 multi token transfer. Each token ID is its own token, with its own amount"
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+)
+
+// prices is the price of one ether in each fiat currency, e.g. "USD", from the -prices file
+var prices = map[string]*big.Rat{}
+
+// loadPrices reads a json object of fiat currencies to the price of one ether, like {"USD": "2500.12"}.
+// Prices are strings so they convert exactly
+func loadPrices(path string) error {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var table map[string]string
+	if err := json.Unmarshal(buf, &table); err != nil {
+		return err
+	}
+	for currency, price := range table {
+		p, ok := new(big.Rat).SetString(price)
+		if !ok {
+			return fmt.Errorf("%s: invalid price %q", currency, price)
+		}
+		prices[currency] = p
+	}
+	return nil
+}
+
+// formatUnits writes wei as an exact decimal number of units of 10^decimals wei, without trailing zeros
+func formatUnits(wei *big.Int, decimals int) string {
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, frac := new(big.Int).QuoRem(new(big.Int).Abs(wei), unit, new(big.Int))
+	s := whole.String()
+	if frac.Sign() != 0 {
+		digits := strings.Repeat("0", decimals-len(frac.String())) + frac.String()
+		s += "." + strings.TrimRight(digits, "0")
+	}
+	if wei.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// etherAmount writes an amount of wei in ether, followed by the exact wei and its fiat value
+func etherAmount(wei *big.Int) string {
+	if wei.Sign() == 0 {
+		return "0 ETH"
+	}
+	extra := append([]string{wei.String() + " wei"}, fiatValues(wei)...)
+	return fmt.Sprintf("%s ETH (%s)", formatUnits(wei, 18), strings.Join(extra, ", "))
+}
+
+// gweiAmount writes a price per gas in gwei, followed by the exact wei
+func gweiAmount(wei *big.Int) string {
+	if wei.Sign() == 0 {
+		return "0 gwei"
+	}
+	return fmt.Sprintf("%s gwei (%s wei)", formatUnits(wei, 9), wei)
+}
+
+// fiatValues converts wei to every currency in the price table, rounded to cents
+func fiatValues(wei *big.Int) []string {
+	currencies := make([]string, 0, len(prices))
+	for currency := range prices {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	var vals []string
+	for _, currency := range currencies {
+		v := new(big.Rat).SetFrac(wei, new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+		v.Mul(v, prices[currency])
+		vals = append(vals, fmt.Sprintf("%s %s", v.FloatString(2), currency))
+	}
+	return vals
+}